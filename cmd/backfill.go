@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"testYTask/internal/app"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/models"
+	"time"
+)
+
+// runBackfill разбирает аргументы подкоманды backfill и выполняет дозагрузку истории.
+//
+// Пример:
+//
+//	go run ./cmd backfill -coin bitcoin -currency USD -from 2024-01-01 -to 2024-06-01
+//	go run ./cmd backfill -resume 42
+//
+// Даты принимаются как unix-время в секундах, YYYY-MM-DD или RFC3339.
+func runBackfill(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	coin := flags.String("coin", "", "coin id, ticker, name or alias, e.g. bitcoin or BTC")
+	currency := flags.String("currency", "USD", "quote currency")
+	from := flags.String("from", "", "start of the period (unix seconds, YYYY-MM-DD or RFC3339), default: to minus 30 days")
+	to := flags.String("to", "", "end of the period (unix seconds, YYYY-MM-DD or RFC3339), default: now")
+	resume := flags.Int64("resume", 0, "id of an existing backfill job to continue")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *resume == 0 && *coin == "" {
+		flags.Usage()
+		return fmt.Errorf("either -coin or -resume is required")
+	}
+
+	req := &models.BackfillRequest{
+		Coin:     *coin,
+		Currency: *currency,
+	}
+
+	var err error
+	if req.From, err = parseTime(*from); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if req.To, err = parseTime(*to); err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	job, err := app.NewApp(cfg).Backfill(ctx, req, *resume)
+	if job != nil {
+		fmt.Printf("backfill #%d %s/%s: status=%s cursor=%d fetched=%d inserted=%d\n",
+			job.ID, job.Coin, job.Currency, job.Status, job.Cursor, job.Fetched, job.Inserted)
+	}
+	return err
+}
+
+// parseTime разбирает метку времени; пустая строка означает значение по умолчанию (0).
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.Unix(), nil
+	}
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+	return moment.Unix(), nil
+}
@@ -18,6 +18,16 @@ func main() {
 	cfg := config.GetConfig()
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
+
+	// Подкоманда backfill выполняет дозагрузку истории и завершает процесс без запуска сервера.
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		if err := runBackfill(ctx, cfg, os.Args[2:]); err != nil {
+			log.Printf("[BACKFILL] %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	application := app.NewApp(cfg)
 	if err := application.Init(ctx); err != nil {
 		panic(fmt.Sprintf("Failed to start application %v", zap.Error(err)))
@@ -7,10 +7,20 @@ import (
 	"testYTask/internal/delivery/http/server"
 	"testYTask/internal/delivery/http/v1/handlers"
 	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
 	cli "testYTask/internal/http"
 	"testYTask/internal/infrastructure/db"
 	"testYTask/internal/infrastructure/db/repository"
+	"testYTask/internal/usecase/alert"
+	"testYTask/internal/usecase/analytics"
+	"testYTask/internal/usecase/backfill"
+	"testYTask/internal/usecase/convert"
+	"testYTask/internal/usecase/gaps"
+	"testYTask/internal/usecase/indicators"
 	"testYTask/internal/usecase/job"
+	"testYTask/internal/usecase/market"
+	"testYTask/internal/usecase/registry"
+	"testYTask/internal/usecase/stream"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -54,6 +64,9 @@ func (a *App) Init(ctx context.Context) error {
 		panic(err)
 	}
 
+	// Формат цен в ответах API: числом (по умолчанию) или строкой
+	models.SetDecimalsAsStrings(a.cfg.App.PriceAsString)
+
 	// Установка подключения к PostgreSQL
 	a.db, err = db.Connection(ctx, a.cfg.Db)
 	if err != nil {
@@ -64,35 +77,67 @@ func (a *App) Init(ctx context.Context) error {
 	// Создание HTTP клиента для сервиса
 	registryClient := cli.NewRegistryClient(a.cfg.Exchange)
 
-	mapCoins, err := registryClient.LoadValidCoins(ctx)
-	if err != nil {
-		zap.L().Error("LoadValidCoins failed", zap.Error(err))
-		panic(err)
+	// Инициализация потока новых цен для подключённых клиентов
+	hub := stream.NewHub()
+
+	// Инициализация реестра валидных монет: при недоступности источника используется сохранённая копия
+	coinRegistry := registry.NewRegistry(registryClient, repository.NewRegistryRepository(a.db), a.cfg.Exchange.Aliases)
+	if err = coinRegistry.Init(ctx); err != nil {
+		zap.L().Error("Coin registry is empty until the next refresh", zap.Error(err))
 	}
 
+	// Публикация метрик
+	InitMetrics(registryClient, hub, coinRegistry)
+
 	// Инициализация репозиториев PostgreSQL
 	majorRepository := repository.NewMajorRepository(a.db)
 	jobRepository := repository.NewJobRepository(a.db)
+	backfillRepository := repository.NewBackfillRepository(a.db)
+	gapRepository := repository.NewGapRepository(a.db)
+	alertRepository := repository.NewAlertRepository(a.db)
+
+	// Инициализация сервиса дозагрузки истории
+	backfiller := backfill.NewBackfiller(backfillRepository, registryClient)
+
+	// Инициализация поиска пропусков в истории цен
+	gapDetector := gaps.NewDetector(a.cfg.Gaps, gapRepository, backfiller)
+
+	// Инициализация оповещений о ценах и их доставки на вебхуки
+	webhookClient := cli.NewWebhookClient(a.cfg.Alerts)
+	alertService := alert.NewService(alertRepository, majorRepository, webhookClient)
+	alertDispatcher := alert.NewDispatcher(alertRepository, webhookClient)
 
 	// Инициализация HTTP обработчиков
-	majorHandler := handlers.NewMajorHandler(majorRepository, mapCoins)
-	commonHandler := http.NewCommonHandler(a.db)
+	majorHandler := handlers.NewMajorHandler(majorRepository, backfiller, coinRegistry)
+	commonHandler := http.NewCommonHandler(a.db, registryClient)
+	providerHandler := handlers.NewProviderHandler(registryClient)
+	convertHandler := handlers.NewConvertHandler(convert.NewConverter(majorRepository), coinRegistry)
+	backfillHandler := handlers.NewBackfillHandler(backfiller, coinRegistry)
+	gapHandler := handlers.NewGapHandler(gapDetector, coinRegistry)
+	streamHandler := handlers.NewStreamHandler(hub, coinRegistry, a.cfg.Cors.AllowOrigins)
+	alertHandler := handlers.NewAlertHandler(alertService, coinRegistry)
+	coinHandler := handlers.NewCoinHandler(coinRegistry)
+	marketHandler := handlers.NewMarketHandler(market.NewService(majorRepository))
+	indicatorHandler := handlers.NewIndicatorHandler(indicators.NewService(majorRepository), coinRegistry)
+	analyticsHandler := handlers.NewAnalyticsHandler(analytics.NewService(majorRepository), coinRegistry)
 
 	// Инициализация задачи для загрузки
 	uploadJob := job.NewUploadJob(
 		jobRepository,
 		registryClient,
+		hub,
+		alertService,
 	)
 
 	//// Инициализация планировщика задач
-	a.scheduler, err = InitScheduler(uploadJob)
+	a.scheduler, err = InitScheduler(uploadJob, backfiller, gapDetector, alertDispatcher, coinRegistry)
 	if err != nil {
 		return err
 	}
 
 	// Создание и регистрация маршрутов HTTP-сервера
 	navigator := server.NewNavigator(a.cfg)
-	navigator.RegisterRoutes(commonHandler, majorHandler)
+	navigator.RegisterRoutes(commonHandler, majorHandler, providerHandler, convertHandler, backfillHandler, gapHandler, streamHandler, alertHandler, coinHandler, marketHandler, indicatorHandler, analyticsHandler)
 
 	a.nexus = server.NewNexus(a.cfg, navigator.Engine)
 	return nil
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+	cli "testYTask/internal/http"
+	"testYTask/internal/infrastructure/db"
+	"testYTask/internal/infrastructure/db/repository"
+	"testYTask/internal/usecase/backfill"
+	"testYTask/internal/usecase/registry"
+
+	"go.uber.org/zap"
+)
+
+// Backfill выполняет дозагрузку истории из командной строки без запуска HTTP-сервера.
+//
+// Если resumeID больше нуля, продолжается существующая задача, иначе создаётся новая
+// (или продолжается незавершённая задача с теми же параметрами). Задача выполняется до конца
+// в текущем процессе; при отмене контекста она возвращается в очередь с сохранённым курсором.
+//
+// Параметры:
+//   - ctx: контекст выполнения
+//   - req: монета, валюта и период дозагрузки
+//   - resumeID: id задачи для продолжения
+//
+// Возвращает итоговое состояние задачи или ошибку.
+func (a *App) Backfill(ctx context.Context, req *models.BackfillRequest, resumeID int64) (*models.BackfillJob, error) {
+	var err error
+
+	if err = InitLogger(a.cfg.App.Stage); err != nil {
+		return nil, err
+	}
+
+	a.db, err = db.Connection(ctx, a.cfg.Db)
+	if err != nil {
+		zap.L().Error("NewConnect failed", zap.Error(err))
+		return nil, err
+	}
+	defer a.closeConnection()
+
+	registryClient := cli.NewRegistryClient(a.cfg.Exchange)
+	backfiller := backfill.NewBackfiller(repository.NewBackfillRepository(a.db), registryClient)
+
+	if resumeID == common.Zero {
+		coinRegistry := registry.NewRegistry(registryClient, repository.NewRegistryRepository(a.db), a.cfg.Exchange.Aliases)
+		if err := coinRegistry.Init(ctx); err != nil {
+			zap.L().Error("Coin registry init failed", zap.Error(err))
+			return nil, err
+		}
+		coin, err := coinRegistry.Resolve(req.Coin)
+		if err != nil {
+			return nil, err
+		}
+		req.Coin = coin.ID
+
+		job, err := backfiller.Enqueue(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resumeID = job.ID
+	}
+
+	return backfiller.Resume(ctx, resumeID)
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"expvar"
+	"testYTask/internal/domain/interfaces"
+
+	"go.uber.org/zap"
+)
+
+// InitMetrics публикует метрики приложения через expvar (маршрут /debug/vars).
+//
+// Параметры:
+//   - registryClient: клиент источников цен, публикуются лимиты и состояние автоматов защиты
+//   - hub: поток цен, публикуются число подписчиков и счётчики событий
+//   - coinRegistry: реестр валидных монет, публикуются его размер и результат последнего обновления
+func InitMetrics(registryClient interfaces.RegistryClientI, hub interfaces.StreamHubI, coinRegistry interfaces.CoinRegistryI) {
+	expvar.Publish("providers", expvar.Func(func() any {
+		return registryClient.Budgets()
+	}))
+	expvar.Publish("stream", expvar.Func(func() any {
+		return hub.Stats()
+	}))
+	expvar.Publish("registry", expvar.Func(func() any {
+		return coinRegistry.Stats()
+	}))
+
+	zap.L().Info("Successfully initialized metrics")
+}
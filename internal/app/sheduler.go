@@ -13,11 +13,15 @@ import (
 //
 // Параметры:
 //   - uploadJob: задача, которая будет запущена по расписанию
+//   - backfillJob: задача обработки очереди дозагрузки истории
+//   - gapJob: задача поиска пропусков в истории цен
+//   - alertJob: задача доставки оповещений на вебхуки
+//   - registryJob: задача обновления реестра валидных монет
 //
 // Возвращает:
 //   - объект планировщика задач
 //   - ошибку, если произошла ошибка при создании или инициализации планировщика
-func InitScheduler(uploadJob interfaces.JobI) (gocron.Scheduler, error) {
+func InitScheduler(uploadJob, backfillJob, gapJob, alertJob, registryJob interfaces.JobI) (gocron.Scheduler, error) {
 	location, err := time.LoadLocation("Asia/Almaty")
 	if err != nil {
 		zap.L().Error(
@@ -39,7 +43,7 @@ func InitScheduler(uploadJob interfaces.JobI) (gocron.Scheduler, error) {
 		return nil, nErr
 	}
 
-	if err = initJobs(scheduler, uploadJob); err != nil {
+	if err = initJobs(scheduler, uploadJob, backfillJob, gapJob, alertJob, registryJob); err != nil {
 		zap.L().Error(
 			"Error initializing scheduler jobs",
 			zap.Error(err),
@@ -56,9 +60,13 @@ func InitScheduler(uploadJob interfaces.JobI) (gocron.Scheduler, error) {
 // Параметры:
 //   - scheduler: объект планировщика задач
 //   - uploadJob: задача, которая будет запущена по расписанию
+//   - backfillJob: задача обработки очереди дозагрузки истории
+//   - gapJob: задача поиска пропусков в истории цен
+//   - alertJob: задача доставки оповещений на вебхуки
+//   - registryJob: задача обновления реестра валидных монет
 //
 // Возвращает ошибку, если добавление задачи завершилось неудачно.
-func initJobs(scheduler gocron.Scheduler, uploadJob interfaces.JobI) error {
+func initJobs(scheduler gocron.Scheduler, uploadJob, backfillJob, gapJob, alertJob, registryJob interfaces.JobI) error {
 	if _, err := scheduler.NewJob(
 		//gocron.DailyJob(
 		//	1, // сколько раз в день запускать.
@@ -82,6 +90,71 @@ func initJobs(scheduler gocron.Scheduler, uploadJob interfaces.JobI) error {
 		zap.L().Info("Successfully initialized uploadJob job")
 	}
 
+	// Задача дозагрузки может выполняться дольше интервала, поэтому одновременно работает только один экземпляр.
+	if _, err := scheduler.NewJob(
+		gocron.DurationJob(common.BackfillSchedulerTick),
+		gocron.NewTask(
+			backfillJob.Run,
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	); err != nil {
+		zap.L().Error(
+			"Error initializing backfillJob job",
+			zap.Error(err),
+		)
+		return err
+	} else {
+		zap.L().Info("Successfully initialized backfillJob job")
+	}
+
+	if _, err := scheduler.NewJob(
+		gocron.DurationJob(common.GapScanInterval),
+		gocron.NewTask(
+			gapJob.Run,
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	); err != nil {
+		zap.L().Error(
+			"Error initializing gapJob job",
+			zap.Error(err),
+		)
+		return err
+	} else {
+		zap.L().Info("Successfully initialized gapJob job")
+	}
+
+	if _, err := scheduler.NewJob(
+		gocron.DurationJob(common.AlertDispatchInterval),
+		gocron.NewTask(
+			alertJob.Run,
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	); err != nil {
+		zap.L().Error(
+			"Error initializing alertJob job",
+			zap.Error(err),
+		)
+		return err
+	} else {
+		zap.L().Info("Successfully initialized alertJob job")
+	}
+
+	if _, err := scheduler.NewJob(
+		gocron.DurationJob(common.CoinRegistryRefresh),
+		gocron.NewTask(
+			registryJob.Run,
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	); err != nil {
+		zap.L().Error(
+			"Error initializing registryJob job",
+			zap.Error(err),
+		)
+		return err
+	} else {
+		zap.L().Info("Successfully initialized registryJob job")
+	}
+
 	zap.L().Info("Successfully initialized all jobs")
 	return nil
 }
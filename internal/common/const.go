@@ -11,12 +11,190 @@ const (
 	ReqTimePrice           = time.Second * 30
 	PostgresDBQueryTimeout = time.Minute * 5
 
-	statusOK        = "OK"
-	statusNotFound  = "NotFound"
-	statusError     = "ERROR"
+	statusOK       = "OK"
+	statusNotFound = "NotFound"
+	statusError    = "ERROR"
+	statusDegraded = "DEGRADED"
+	// Статусы элементов пакетного ответа совпадают со статусами одиночных ответов.
+	ItemOK       = statusOK
+	ItemNotFound = statusNotFound
+	ItemError    = statusError
+
 	DefaultCurrency = "USD"
 	Empty           = ""
 
-	Precision = 8
-	Zero      = 0
+	MaxCurrenciesPerCoin = 10
+
+	// Режимы выбора цены на момент времени.
+	ModeNearest = "nearest"
+	ModeBefore  = "before"
+	ModeAfter   = "after"
+	ModeLinear  = "linear"
+
+	RouteSame         = "same"
+	RouteDirect       = "direct"
+	RouteInverse      = "inverse"
+	RouteTriangulated = "triangulated"
+
+	// Поиск маршрута пересчёта: наибольшее число звеньев маршрута и число маршрутов, которые пробуются по очереди.
+	MaxConversionLegs   = 3
+	MaxConversionRoutes = 5
+
+	Precision     = 8
+	MaxPrecision  = 18
+	RatePrecision = 18
+	Zero          = 0
+
+	MaxBatchPrices = 1000
+
+	// Реестр валидных монет: страницы списка по DefaultCoinsPerPage монет, не больше DefaultListCoinPages страниц.
+	DefaultCoinsPerPage   = 100
+	DefaultListCoinPages  = 4
+	CoinRegistryRefresh   = time.Hour
+	RegistrySourceAPI     = "provider"
+	RegistrySourceStorage = "database"
+
+	// Поиск монет: типы совпадений в порядке убывания качества и размер ответа.
+	MatchExact         = "exact"
+	MatchPrefix        = "prefix"
+	MatchSubstring     = "substring"
+	MatchFuzzy         = "fuzzy"
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 50
+	MaxSearchQuery     = 64
+
+	DefaultHistoryLimit = 500
+	MaxHistoryLimit     = 5000
+
+	// Список отслеживаемых монет: пара считается устаревшей без новых цен дольше DefaultStaleAfter секунд
+	// (три запуска задачи загрузки).
+	DefaultStaleAfter   = 90
+	DefaultWatchedLimit = 100
+	MaxWatchedLimit     = 1000
+
+	// Поля сортировки списка отслеживаемых монет.
+	SortCoin          = "coin"
+	SortAddedAt       = "added_at"
+	SortLastTimestamp = "last_timestamp"
+	SortSamples       = "samples"
+	SortStaleness     = "staleness"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+
+	// Снимок рынка и лидеры роста/падения: изменения в процентах округляются до ChangePrecision знаков.
+	DefaultMoversWindow = "24h"
+	DefaultMoversLimit  = 10
+	MaxMoversLimit      = 100
+	MaxMarketWindow     = int64(30 * 24 * 60 * 60)
+	ChangePrecision     = 4
+
+	DefaultCandleInterval = "1h"
+	DefaultCandleWindow   = 24 * 60 * 60
+	MaxCandles            = 10000
+
+	// Технические индикаторы: перед началом периода загружается IndicatorLookback × (наибольший прогрев)
+	// интервалов, чтобы значения EMA и RSI успели сойтись к значениям по полной истории.
+	DefaultIndicatorWindow = 7 * 24 * 60 * 60
+	IndicatorLookback      = 4
+	MaxIndicators          = 10
+	MaxIndicatorPeriod     = 500
+	MaxBollingerWidth      = 10
+
+	// Средние цены за окно: сэмпл действует до следующего, но не дольше max_gap (по умолчанию — порог пропуска),
+	// доля покрытия окна округляется до CoveragePrecision знаков.
+	DefaultAverageWindow = 24 * 60 * 60
+	DefaultAverageMaxGap = DefaultGapThreshold
+	MaxAverageWindow     = int64(366 * 24 * 60 * 60)
+	CoveragePrecision    = 4
+
+	// Корреляция доходностей: доходности логарифмические (log) или относительные (simple).
+	ReturnsLog                 = "log"
+	ReturnsSimple              = "simple"
+	DefaultCorrelationInterval = "1d"
+	DefaultCorrelationWindow   = 90 * 24 * 60 * 60
+	MaxCorrelationCoins        = 20
+	MinCorrelationObservations = 3
+
+	// Параметры отбраковки выбросов при расчёте согласованной цены (median absolute deviation).
+	ConsensusMADScale     = 1.4826
+	ConsensusMADThreshold = 3.5
+	ConsensusMinDeviation = 0.005
+
+	DefaultRequestsPerMinute = 30
+	DefaultBurst             = 5
+	DefaultMaxRetries        = 3
+	DefaultRetryBaseDelay    = time.Millisecond * 500
+	DefaultRetryMaxDelay     = time.Second * 20
+
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = time.Second * 60
+
+	// Статусы задач дозагрузки истории.
+	BackfillPending = "pending"
+	BackfillRunning = "running"
+	BackfillDone    = "done"
+	BackfillFailed  = "failed"
+
+	// DefaultBackfillWindow — глубина истории, загружаемой при добавлении монеты (сек).
+	DefaultBackfillWindow = 30 * 24 * 60 * 60
+	// BackfillChunkWindow — период одного запроса к источнику; для CoinGecko до 90 дней отдаются часовые точки.
+	BackfillChunkWindow = 30 * 24 * 60 * 60
+	// CoinGeckoHistoryStep — интервал между точками истории CoinGecko за период не длиннее BackfillChunkWindow (сек).
+	CoinGeckoHistoryStep = 60 * 60
+	// BackfillLease — время, после которого задача в статусе running без прогресса считается прерванной.
+	BackfillLease         = time.Minute * 5
+	DefaultBackfillLimit  = 50
+	MaxBackfillLimit      = 500
+	ReqTimeHistory        = time.Second * 60
+	BackfillSchedulerTick = time.Second * 30
+
+	// Параметры потока цен: буфер подписки (в запусках задачи загрузки), интервал heartbeat, таймаут записи и ожидания pong.
+	StreamBufferSize     = 16
+	StreamMaxSubscribers = 1000
+	StreamHeartbeat      = time.Second * 15
+	StreamWriteTimeout   = time.Second * 10
+	StreamPongWait       = time.Second * 60
+	StreamReadLimit      = 4096
+
+	// Условия правил оповещения.
+	AlertAbove  = "above"
+	AlertBelow  = "below"
+	AlertChange = "change"
+
+	// Наибольшее окно изменения цены для условия change.
+	MaxAlertWindow = int64(30 * 24 * 60 * 60)
+
+	// Статусы доставки оповещений.
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+
+	// Параметры доставки оповещений: повторы с экспоненциальной задержкой от AlertRetryBaseDelay до AlertRetryMaxDelay.
+	AlertDispatchInterval = time.Second * 10
+	AlertDispatchBatch    = 50
+	AlertDispatchWorkers  = 8
+	AlertDeliveryLease    = time.Minute * 2
+	AlertMaxAttempts      = 8
+	AlertRetryBaseDelay   = time.Second * 10
+	AlertRetryMaxDelay    = time.Minute * 30
+	WebhookTimeout        = time.Second * 10
+	AlertSecretBytes      = 32
+	DefaultDeliveryLimit  = 100
+	MaxDeliveryLimit      = 1000
+
+	// Статусы пропусков в истории цен.
+	GapOpen         = "open"
+	GapRepairing    = "repairing"
+	GapRepaired     = "repaired"
+	GapUnrepairable = "unrepairable"
+	GapFailed       = "failed"
+
+	// Значения по умолчанию для поиска пропусков: задача загрузки цен запускается каждые 30 секунд.
+	DefaultGapThreshold = 90
+	DefaultGapLookback  = 24 * 60 * 60
+	GapScanInterval     = time.Minute * 5
+	MaxGapRepairsPerRun = 20
+	DefaultGapLimit     = 100
+	MaxGapLimit         = 1000
 )
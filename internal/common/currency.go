@@ -0,0 +1,57 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SupportedCurrencies содержит валюты котировки, которые можно запросить для отслеживаемой монеты.
+var SupportedCurrencies = map[string]bool{
+	"USD": true,
+	"EUR": true,
+	"GBP": true,
+	"JPY": true,
+	"CNY": true,
+	"RUB": true,
+	"KZT": true,
+	"UAH": true,
+	"TRY": true,
+	"BTC": true,
+	"ETH": true,
+}
+
+// NormalizeCurrency приводит код валюты к верхнему регистру и проверяет, что она поддерживается.
+// Пустое значение заменяется DefaultCurrency.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == Empty {
+		return DefaultCurrency, nil
+	}
+	if !SupportedCurrencies[currency] {
+		return Empty, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	return currency, nil
+}
+
+// NormalizeCurrencies нормализует список валют котировки и удаляет повторы, сохраняя порядок.
+// Пустой список заменяется списком из DefaultCurrency.
+func NormalizeCurrencies(currencies []string) ([]string, error) {
+	if len(currencies) == Zero {
+		return []string{DefaultCurrency}, nil
+	}
+
+	seen := make(map[string]bool, len(currencies))
+	result := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		normalized, err := NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	return result, nil
+}
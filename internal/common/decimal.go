@@ -0,0 +1,42 @@
+package common
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ScalePrice переводит цену в целое число с адаптивной точностью для хранения в currency_prices.
+//
+// Точность равна числу знаков после запятой в исходном значении, но не меньше Precision
+// и не больше MaxPrecision; лишние знаки округляются (half away from zero). Если целое
+// значение не помещается в int64, точность уменьшается.
+//
+// Возвращает целое значение и точность: price = scaled * 10^-precision.
+func ScalePrice(price decimal.Decimal) (int64, int) {
+	precision := Precision
+	if exponent := int(price.Exponent()); -exponent > precision {
+		precision = min(-exponent, MaxPrecision)
+	}
+
+	for ; precision > Zero; precision-- {
+		scaled := price.Round(int32(precision)).Shift(int32(precision))
+		if scaled.BigInt().IsInt64() {
+			return scaled.IntPart(), precision
+		}
+	}
+	return price.Round(0).IntPart(), Zero
+}
+
+// InterpolatePrice линейно интерполирует цену в момент at между точками (from, fromPrice) и (to, toPrice).
+// Результат округляется до RatePrecision знаков после запятой. При from == to возвращается fromPrice.
+func InterpolatePrice(from int64, fromPrice decimal.Decimal, to int64, toPrice decimal.Decimal, at int64) decimal.Decimal {
+	if from == to {
+		return fromPrice
+	}
+	delta := toPrice.Sub(fromPrice).Mul(decimal.NewFromInt(at - from))
+	return fromPrice.Add(delta.DivRound(decimal.NewFromInt(to-from), RatePrecision))
+}
+
+// UnscalePrice восстанавливает точное значение цены из целого числа и точности.
+func UnscalePrice(scaled int64, precision int) decimal.Decimal {
+	return decimal.New(scaled, -int32(precision))
+}
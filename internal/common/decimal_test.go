@@ -0,0 +1,71 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestScalePrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     string
+		scaled    int64
+		precision int
+		exact     bool
+	}{
+		{name: "integer", price: "100", scaled: 10000000000, precision: Precision, exact: true},
+		{name: "regular price", price: "117150.25", scaled: 11715025000000, precision: Precision, exact: true},
+		{name: "negative", price: "-1.5", scaled: -150000000, precision: Precision, exact: true},
+		{name: "more digits than Precision", price: "0.000000001234", scaled: 1234, precision: 12, exact: true},
+		{name: "MaxPrecision digits", price: "0.000000000000000001", scaled: 1, precision: MaxPrecision, exact: true},
+		{name: "beyond MaxPrecision rounds half away from zero", price: "0.0000000000000000015", scaled: 2, precision: MaxPrecision},
+		{name: "beyond MaxPrecision rounds down", price: "0.0000000000000000014", scaled: 1, precision: MaxPrecision},
+		{name: "largest int64 at Precision", price: "92233720368.54775807", scaled: 9223372036854775807, precision: Precision, exact: true},
+		{name: "int64 overflow lowers precision", price: "92233720368.54775808", scaled: 922337203685477581, precision: 7},
+		{name: "fine price of a large value", price: "1000000000.000000000001", scaled: 1000000000000000000, precision: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := decimal.RequireFromString(tt.price)
+			scaled, precision := ScalePrice(price)
+			if scaled != tt.scaled || precision != tt.precision {
+				t.Fatalf("ScalePrice(%s) = %d, %d; want %d, %d", tt.price, scaled, precision, tt.scaled, tt.precision)
+			}
+
+			restored := UnscalePrice(scaled, precision)
+			if tt.exact && !restored.Equal(price) {
+				t.Errorf("round trip: got %s, want %s", restored, tt.price)
+			}
+			if !restored.Equal(price.Round(int32(precision))) {
+				t.Errorf("round trip: got %s, want %s rounded to %d digits", restored, tt.price, precision)
+			}
+		})
+	}
+}
+
+func TestInterpolatePrice(t *testing.T) {
+	tests := []struct {
+		name               string
+		from, to, at       int64
+		fromPrice, toPrice string
+		want               string
+	}{
+		{name: "midpoint", from: 0, to: 60, at: 30, fromPrice: "100", toPrice: "110", want: "105"},
+		{name: "at the lower point", from: 0, to: 60, at: 0, fromPrice: "100", toPrice: "110", want: "100"},
+		{name: "at the upper point", from: 0, to: 60, at: 60, fromPrice: "100", toPrice: "110", want: "110"},
+		{name: "falling price", from: 0, to: 30, at: 10, fromPrice: "3", toPrice: "2", want: "2.666666666666666667"},
+		{name: "same timestamp", from: 60, to: 60, at: 60, fromPrice: "100", toPrice: "110", want: "100"},
+		{name: "tiny prices keep precision", from: 0, to: 2, at: 1, fromPrice: "0.000000001", toPrice: "0.000000002", want: "0.0000000015"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InterpolatePrice(tt.from, decimal.RequireFromString(tt.fromPrice), tt.to, decimal.RequireFromString(tt.toPrice), tt.at)
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,68 @@
 package common
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testYTask/internal/domain/models"
+)
 
 var (
-	ErrCoinNotFound  = errors.New("coin not found")
-	ErrPriceNotFound = errors.New("response not found")
+	ErrCoinNotFound      = errors.New("coin not found")
+	ErrEmptyRegistry     = errors.New("coin registry is empty")
+	ErrAmbiguousCoin     = errors.New("coin identifier is ambiguous")
+	ErrPriceNotFound     = errors.New("response not found")
+	ErrPriceTooFar       = errors.New("nearest price sample is farther than max distance")
+	ErrTooManyCurrencies = errors.New("too many currencies for coin")
+
+	ErrInvalidInterval     = errors.New("invalid interval")
+	ErrInvalidIndicator    = errors.New("invalid indicator")
+	ErrNotEnoughData       = errors.New("not enough aligned observations")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrNoConversionRoute   = errors.New("no conversion route")
+
+	ErrSymbolNotMapped   = errors.New("coin is not mapped for provider")
+	ErrNoProviders       = errors.New("no price providers available")
+	ErrUnexpectedPayload = errors.New("unexpected provider payload")
+	ErrNoQuotes          = errors.New("no quotes to build consensus")
+
+	ErrRateLimited      = errors.New("provider rate limit exceeded")
+	ErrUpstreamFailure  = errors.New("provider responded with server error")
+	ErrUnexpectedStatus = errors.New("provider responded with unexpected status")
+	ErrCircuitOpen      = errors.New("provider circuit breaker is open")
+
+	ErrNoHistoryProvider  = errors.New("no provider supports historical prices")
+	ErrInvalidRange       = errors.New("invalid time range")
+	ErrBackfillNotFound   = errors.New("backfill job not found")
+	ErrBackfillNotClaimed = errors.New("backfill job is finished or processed by another worker")
+
+	ErrTooManySubscribers = errors.New("too many stream subscribers")
+	ErrSlowConsumer       = errors.New("stream subscriber is too slow")
+
+	ErrAlertNotFound   = errors.New("alert rule not found")
+	ErrInvalidAlert    = errors.New("invalid alert rule")
+	ErrWebhookRejected = errors.New("webhook responded with non-2xx status")
+	ErrWebhookBlocked  = errors.New("webhook target address is not allowed")
+
+	ErrGapNotFound      = errors.New("price gap not found")
+	ErrGapNotRepairable = errors.New("price gap is already repaired or being repaired")
 )
+
+// AmbiguousCoinError сообщает, что тикер или название монеты соответствует нескольким ID реестра.
+// Candidates упорядочены по рыночной капитализации. errors.Is(err, ErrAmbiguousCoin) возвращает true.
+type AmbiguousCoinError struct {
+	Query      string             `json:"query"`
+	Candidates []*models.CoinInfo `json:"candidates"`
+}
+
+func (e *AmbiguousCoinError) Error() string {
+	ids := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		ids = append(ids, candidate.ID)
+	}
+	return fmt.Sprintf("%s: %q matches %s", ErrAmbiguousCoin, e.Query, strings.Join(ids, ", "))
+}
+
+func (e *AmbiguousCoinError) Unwrap() error {
+	return ErrAmbiguousCoin
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// namedIntervals содержит поддерживаемые сокращения интервалов свечей и окон изменения цены.
+var namedIntervals = map[string]int64{
+	"1m":  60,
+	"5m":  5 * 60,
+	"15m": 15 * 60,
+	"30m": 30 * 60,
+	"1h":  60 * 60,
+	"4h":  4 * 60 * 60,
+	"1d":  24 * 60 * 60,
+	"24h": 24 * 60 * 60,
+	"7d":  7 * 24 * 60 * 60,
+	"1w":  7 * 24 * 60 * 60,
+}
+
+// ParseInterval разбирает интервал агрегации и возвращает его длительность в секундах.
+//
+// Поддерживаются сокращения (1m, 5m, 15m, 30m, 1h, 4h, 1d, 24h, 7d, 1w), строки в формате
+// time.ParseDuration (например, "90s" или "2h30m") и целое число секунд.
+//
+// Возвращает ErrInvalidInterval, если значение не распознано или меньше одной секунды.
+func ParseInterval(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if seconds, ok := namedIntervals[value]; ok {
+		return seconds, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= Zero {
+			return Zero, ErrInvalidInterval
+		}
+		return seconds, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < time.Second {
+		return Zero, ErrInvalidInterval
+	}
+	return int64(duration / time.Second), nil
+}
@@ -33,6 +33,75 @@ func ResponseServerError(c *gin.Context, msg string) {
 	})
 }
 
+// ResponseAccepted отправляет ответ с кодом 202 (Accepted).
+// Используется, когда запрос принят и будет выполнен в фоне.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - msg: сообщение о принятой операции
+//   - data: состояние созданной задачи
+func ResponseAccepted(c *gin.Context, msg string, data interface{}) {
+	c.JSON(http.StatusAccepted, models.Response{
+		Status:  statusOK,
+		Message: msg,
+		Data:    data,
+	})
+}
+
+// ResponseUnauthorized отправляет ответ с кодом 401 (Unauthorized).
+// Используется, когда запрос к закрытому маршруту не прошёл проверку токена.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - msg: сообщение об ошибке
+func ResponseUnauthorized(c *gin.Context, msg string) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, models.Response{
+		Status:  statusError,
+		Message: msg,
+	})
+}
+
+// ResponseForbidden отправляет ответ с кодом 403 (Forbidden).
+// Используется, когда закрытый маршрут недоступен независимо от переданных данных.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - msg: сообщение об ошибке
+func ResponseForbidden(c *gin.Context, msg string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, models.Response{
+		Status:  statusError,
+		Message: msg,
+	})
+}
+
+// ResponseServiceUnavailable отправляет ответ с кодом 503 (Service Unavailable).
+// Используется, когда сервис временно не может принять запрос.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - msg: сообщение об ошибке
+func ResponseServiceUnavailable(c *gin.Context, msg string) {
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.Response{
+		Status:  statusError,
+		Message: msg,
+	})
+}
+
+// ResponseConflict отправляет ответ с кодом 409 (Conflict).
+// Используется, когда запрос нельзя выполнить однозначно, например обозначение монеты соответствует нескольким ID.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - msg: сообщение об ошибке
+//   - data: подробности конфликта (например, список вариантов)
+func ResponseConflict(c *gin.Context, msg string, data interface{}) {
+	c.AbortWithStatusJSON(http.StatusConflict, models.Response{
+		Status:  statusError,
+		Message: msg,
+		Data:    data,
+	})
+}
+
 // ResponseNotFound отправляет ответ с кодом 404 (Not Found).
 // Используется, когда запрошенный ресурс не найден.
 //
@@ -60,3 +129,23 @@ func ResponseSuccess(c *gin.Context, msg string, data interface{}) {
 		Data:    data,
 	})
 }
+
+// ResponseHealth отправляет подробный ответ проверки работоспособности.
+// Код ответа 503, если недоступен обязательный компонент, иначе 200;
+// статус "DEGRADED" означает, что часть необязательных компонентов неработоспособна.
+//
+// Параметры:
+//   - c: контекст Gin, через который формируется ответ
+//   - unavailable: недоступен обязательный компонент
+//   - degraded: неработоспособен необязательный компонент
+//   - data: состояние компонентов
+func ResponseHealth(c *gin.Context, unavailable, degraded bool, data interface{}) {
+	switch {
+	case unavailable:
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.Response{Status: statusError, Data: data})
+	case degraded:
+		c.JSON(http.StatusOK, models.Response{Status: statusDegraded, Data: data})
+	default:
+		c.JSON(http.StatusOK, models.Response{Status: statusOK, Data: data})
+	}
+}
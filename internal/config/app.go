@@ -18,4 +18,31 @@ type AppConf struct {
 	Stage string        `json:"APP_STAGE"`
 	RTO   time.Duration `json:"APP_RTO"`
 	WTO   time.Duration `json:"APP_WTO"`
+	// PriceAsString включает сериализацию цен в JSON строками ("0.000000001") вместо чисел.
+	PriceAsString bool `json:"APP_PRICE_AS_STRING"`
+	// AdminToken — значение заголовка X-Admin-Token для маршрутов /api/v1/admin.
+	AdminToken string `json:"APP_ADMIN_TOKEN"`
+}
+
+// GapsConf содержит настройки поиска пропусков в истории цен.
+//
+// Поля:
+//   - Enabled: включён ли поиск пропусков.
+//   - ThresholdSec: интервал между соседними ценами, начиная с которого он считается пропуском (сек).
+//   - LookbackSec: глубина сканирования от текущего момента (сек).
+//   - AutoRepair: ставить ли задачи дозагрузки истории для найденных пропусков.
+type GapsConf struct {
+	Enabled      bool  `json:"ENABLED"`
+	ThresholdSec int64 `json:"THRESHOLD_SEC"`
+	LookbackSec  int64 `json:"LOOKBACK_SEC"`
+	AutoRepair   bool  `json:"AUTO_REPAIR"`
+}
+
+// AlertsConf содержит настройки оповещений о ценах.
+//
+// Поля:
+//   - WebhookAllowList: хосты и адреса (IP или CIDR), на которые разрешена доставка вебхуков, даже если они
+//     относятся к loopback, link-local или частным сетям. Остальные такие адреса отклоняются.
+type AlertsConf struct {
+	WebhookAllowList []string `json:"WEBHOOK_ALLOW_LIST"`
 }
@@ -18,6 +18,8 @@ type Config struct {
 	Db       *DbConf      `json:"DB"`
 	Exchange *ApiExchange `json:"EXCHANGE"`
 	Swagger  *SwagConf    `json:"SWAGGER"`
+	Gaps     *GapsConf    `json:"GAPS"`
+	Alerts   *AlertsConf  `json:"ALERTS"`
 }
 
 // GetConfig загружает и возвращает конфигурацию из указанного файла.
@@ -12,9 +12,69 @@ type DbConf struct {
 // ApiExchange содержит конфигурацию для работы с внешними API.
 //
 // Поля:
-//   - Url: адрес API для получения цены валюты.
-//   - UrlListCoins: адрес API для получения списка доступных монет
+//   - UrlListCoins: адрес API для получения списка доступных монет; параметр page подставляется при загрузке
+//   - ListCoinPages: наибольшее число загружаемых страниц списка монет (0 — значение по умолчанию)
+//   - Aliases: пользовательские обозначения монет, например "xbt": "bitcoin"; имеют приоритет перед тикерами и названиями
+//   - Providers: список источников цен, опрашиваемых в порядке приоритета.
 type ApiExchange struct {
-	Url          string `json:"URL_PRICE"`
-	UrlListCoins string `json:"URL_LIST_COIN"`
+	UrlListCoins  string            `json:"URL_LIST_COIN"`
+	ListCoinPages int               `json:"LIST_COIN_PAGES"`
+	Aliases       map[string]string `json:"ALIASES"`
+	Providers     []*ProviderConf   `json:"PROVIDERS"`
+}
+
+// ProviderConf содержит настройки одного источника цен.
+//
+// Поля:
+//   - Name: тип адаптера (coingecko, binance, kraken, coincap).
+//   - Enabled: включён ли источник.
+//   - Priority: приоритет источника, меньшее значение опрашивается раньше.
+//   - Url: базовый адрес API источника.
+//   - ApiKey: ключ API, если источник его требует.
+//   - BatchSize: максимальное число монет в одном запросе (0 — значение адаптера по умолчанию).
+//   - RateLimit: лимиты запросов и политика повторов источника.
+//   - Breaker: настройки автомата защиты (circuit breaker) источника.
+//   - Symbols: соответствие ID монеты (например, "bitcoin") символу источника (например, "BTC").
+//   - Quotes: соответствие валюты котировки (например, "USD") активу источника (например, "USDT").
+type ProviderConf struct {
+	Name      string            `json:"NAME"`
+	Enabled   bool              `json:"ENABLED"`
+	Priority  int               `json:"PRIORITY"`
+	Url       string            `json:"URL"`
+	ApiKey    string            `json:"API_KEY"`
+	BatchSize int               `json:"BATCH_SIZE"`
+	RateLimit *RateLimitConf    `json:"RATE_LIMIT"`
+	Breaker   *BreakerConf      `json:"BREAKER"`
+	Symbols   map[string]string `json:"SYMBOLS"`
+	Quotes    map[string]string `json:"QUOTES"`
+}
+
+// RateLimitConf содержит лимиты запросов к источнику и политику повторов.
+// Нулевые значения заменяются значениями по умолчанию; для MaxRetries значение по умолчанию
+// используется, только если поле не задано.
+//
+// Поля:
+//   - RequestsPerMinute: скорость пополнения токен-бакета (запросов в минуту).
+//   - Burst: ёмкость токен-бакета.
+//   - MaxRetries: число повторов при 429/5xx и сетевых ошибках (0 — без повторов).
+//   - BaseDelayMs: базовая задержка экспоненциального backoff (мс).
+//   - MaxDelayMs: максимальная задержка между повторами (мс). Если Retry-After больше,
+//     источник блокируется до указанного времени без повторов.
+type RateLimitConf struct {
+	RequestsPerMinute float64 `json:"REQUESTS_PER_MINUTE"`
+	Burst             int     `json:"BURST"`
+	MaxRetries        *int    `json:"MAX_RETRIES"`
+	BaseDelayMs       int     `json:"BASE_DELAY_MS"`
+	MaxDelayMs        int     `json:"MAX_DELAY_MS"`
+}
+
+// BreakerConf содержит настройки автомата защиты источника.
+// Нулевые значения заменяются значениями по умолчанию.
+//
+// Поля:
+//   - FailureThreshold: число подряд неудачных запросов, после которого автомат размыкается.
+//   - CooldownSec: время в разомкнутом состоянии до пробного запроса (сек).
+type BreakerConf struct {
+	FailureThreshold int `json:"FAILURE_THRESHOLD"`
+	CooldownSec      int `json:"COOLDOWN_SEC"`
 }
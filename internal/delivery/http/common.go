@@ -3,7 +3,10 @@ package http
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"testYTask/internal/common"
 	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,28 +32,62 @@ func NewCommonHandler(sentinels ...interface{}) *CommonHandler {
 
 // HealthCheck обрабатывает запрос /healthz и проверяет работоспособность компонентов.
 //
+// Компоненты PingerI обязательны: ошибка Ping приводит к ответу 503.
+// Компоненты HealthReporterI необязательны: их неработоспособность отражается
+// только в подробном ответе статусом "DEGRADED".
+//
+// Параметры запроса (query):
+//   - detail: вернуть JSON с состоянием каждого компонента (bool, по умолчанию false)
+//
 // Параметры:
 //   - c: контекст запроса Gin
 func (h *CommonHandler) HealthCheck(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	detail, _ := strconv.ParseBool(c.Query("detail"))
+
+	var (
+		components  = make([]*models.HealthComponent, 0, len(h.sentinels))
+		unavailable bool
+		degraded    bool
+	)
+
 	for _, sentinel := range h.sentinels {
 		if sentinel == nil {
 			continue
 		}
 		switch s := sentinel.(type) {
 		case interfaces.PingerI:
+			component := &models.HealthComponent{Name: "database", Healthy: true}
 			if err := s.Ping(ctx); err != nil {
 				zap.L().Error("Database or kafka connection error", zap.Error(err))
-				c.AbortWithStatus(http.StatusServiceUnavailable)
-				return
+				component.Healthy = false
+				component.Detail = err.Error()
+				unavailable = true
 			}
+			components = append(components, component)
+		case interfaces.HealthReporterI:
+			component := s.HealthReport()
+			if !component.Healthy {
+				zap.L().Warn("Component is degraded", zap.String("name:", component.Name))
+				degraded = true
+			}
+			components = append(components, component)
 		default:
 			zap.L().Error("Unknown sentinel type", zap.Any("type", s))
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
 	}
+
+	if detail {
+		common.ResponseHealth(c, unavailable, degraded, components)
+		return
+	}
+	if unavailable {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
 	c.Status(http.StatusOK)
 }
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"crypto/subtle"
+	"testYTask/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// adminAuth проверяет заголовок X-Admin-Token у административных маршрутов.
+// Если токен в конфигурации не задан, маршруты закрыты: все запросы получают 403.
+func adminAuth(token string) gin.HandlerFunc {
+	if token == common.Empty {
+		zap.L().Warn("APP_ADMIN_TOKEN is empty, admin routes are disabled")
+		return func(c *gin.Context) {
+			common.ResponseForbidden(c, "Admin routes are disabled: APP_ADMIN_TOKEN is not configured")
+		}
+	}
+
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Admin-Token")), []byte(token)) != 1 {
+			common.ResponseUnauthorized(c, "Invalid admin token")
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"expvar"
 	"testYTask/docs"
 	"testYTask/internal/config"
 	"testYTask/internal/delivery/http"
@@ -42,7 +43,7 @@ func NewNavigator(cfg *config.Config) *Navigator {
 			&ginzap.Config{
 				TimeFormat: time.RFC1123Z,
 				UTC:        true,
-				SkipPaths:  []string{"/healthz"},
+				SkipPaths:  []string{"/healthz", "/debug/vars"},
 			},
 		),
 	)
@@ -55,6 +56,7 @@ func NewNavigator(cfg *config.Config) *Navigator {
 		}),
 	)
 	Engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	Engine.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	return &Navigator{
 		cfg:    cfg,
@@ -67,7 +69,17 @@ func NewNavigator(cfg *config.Config) *Navigator {
 // Параметры:
 //   - commonHandler: обработчик для общих маршрутов (например, проверки состояния)
 //   - majorHandler: обработчик для основных бизнес-операций
-func (n *Navigator) RegisterRoutes(commonHandler *http.CommonHandler, majorHandler *handlers.MajorHandler) {
+//   - providerHandler: обработчик состояния источников цен
+//   - convertHandler: обработчик пересчёта сумм между монетами и валютами
+//   - backfillHandler: обработчик задач дозагрузки истории (маршруты /admin)
+//   - gapHandler: обработчик пропусков в истории цен
+//   - streamHandler: обработчик потока новых цен (SSE и WebSocket)
+//   - alertHandler: обработчик правил оповещений о ценах (защищены токеном администратора)
+//   - coinHandler: обработчик поиска по реестру валидных монет
+//   - marketHandler: обработчик снимка рынка и лидеров роста/падения
+//   - indicatorHandler: обработчик технических индикаторов по истории цен
+//   - analyticsHandler: обработчик аналитики по нескольким монетам
+func (n *Navigator) RegisterRoutes(commonHandler *http.CommonHandler, majorHandler *handlers.MajorHandler, providerHandler *handlers.ProviderHandler, convertHandler *handlers.ConvertHandler, backfillHandler *handlers.BackfillHandler, gapHandler *handlers.GapHandler, streamHandler *handlers.StreamHandler, alertHandler *handlers.AlertHandler, coinHandler *handlers.CoinHandler, marketHandler *handlers.MarketHandler, indicatorHandler *handlers.IndicatorHandler, analyticsHandler *handlers.AnalyticsHandler) {
 	n.Engine.GET("healthz", commonHandler.HealthCheck)
 	api := n.Engine.Group("/api")
 	{
@@ -75,9 +87,49 @@ func (n *Navigator) RegisterRoutes(commonHandler *http.CommonHandler, majorHandl
 		{
 			currency := v1.Group("/currency")
 			{
+				currency.GET("", majorHandler.ListWatchedCoins)
 				currency.POST("/price", majorHandler.GetPriceForCoin)
+				currency.POST("/price/batch", majorHandler.GetPricesBatch)
 				currency.POST("/add", majorHandler.AddingCoin)
 				currency.DELETE("/remove", majorHandler.DeleteCoin)
+				currency.GET("/:coin/history", majorHandler.GetPriceHistory)
+				currency.GET("/:coin/candles", majorHandler.GetCandles)
+				currency.GET("/:coin/indicators", indicatorHandler.GetIndicators)
+				currency.GET("/:coin/average", majorHandler.GetAveragePrice)
+			}
+			market := v1.Group("/market")
+			{
+				market.GET("/snapshot", marketHandler.GetSnapshot)
+				market.GET("/movers", marketHandler.GetMovers)
+			}
+			v1.GET("/analytics/correlation", analyticsHandler.GetCorrelation)
+			v1.GET("/coins/search", coinHandler.SearchCoins)
+			v1.GET("/providers", providerHandler.GetProviders)
+			v1.GET("/convert", convertHandler.Convert)
+			v1.GET("/gaps", gapHandler.GetGaps)
+
+			stream := v1.Group("/stream")
+			{
+				stream.GET("/sse", streamHandler.StreamSSE)
+				stream.GET("/ws", streamHandler.StreamWS)
+			}
+
+			alerts := v1.Group("/alerts", adminAuth(n.cfg.App.AdminToken))
+			{
+				alerts.POST("", alertHandler.CreateAlert)
+				alerts.GET("", alertHandler.ListAlerts)
+				alerts.GET("/:id", alertHandler.GetAlert)
+				alerts.DELETE("/:id", alertHandler.DeleteAlert)
+				alerts.GET("/:id/deliveries", alertHandler.GetDeliveries)
+			}
+
+			admin := v1.Group("/admin", adminAuth(n.cfg.App.AdminToken))
+			{
+				admin.POST("/backfill", backfillHandler.CreateBackfill)
+				admin.GET("/backfill", backfillHandler.ListBackfills)
+				admin.GET("/backfill/:id", backfillHandler.GetBackfill)
+				admin.POST("/backfill/:id/retry", backfillHandler.RetryBackfill)
+				admin.POST("/gaps/:id/repair", gapHandler.RepairGap)
 			}
 		}
 	}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// AlertHandler обрабатывает запросы на управление правилами оповещений о ценах.
+type AlertHandler struct {
+	alertService interfaces.AlertServiceI
+	coinRegistry interfaces.CoinRegistryI
+}
+
+func NewAlertHandler(alertService interfaces.AlertServiceI, coinRegistry interfaces.CoinRegistryI) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+		coinRegistry: coinRegistry,
+	}
+}
+
+// CreateAlert обрабатывает запрос на создание правила оповещения.
+//
+// Маршрут: POST /api/v1/alerts
+//
+// Параметры запроса (JSON):
+//   - coin: ID, тикер, название или псевдоним монеты (string)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - condition: above, below или change (string)
+//   - threshold: порог цены, для change — порог изменения в процентах (десятичная строка, больше 0)
+//   - window: окно изменения цены для change (int, сек, максимум 30 дней)
+//   - webhook_url: адрес вебхука http или https (string); внутренние адреса запрещены, если не разрешены в ALERTS.WEBHOOK_ALLOW_LIST
+//   - secret: секрет подписи HMAC (string, по умолчанию генерируется)
+//
+// Возможные ответы:
+//   - 200 OK: созданное правило вместе с секретом подписи.
+//   - 400 Bad Request: некорректные входные данные, запрещённый адрес вебхука или монета не найдена.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при сохранении правила.
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start creating alert...")
+
+	req := new(models.AlertRequest)
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		zap.L().Error("ShouldBindJSON error", zap.Error(err))
+		common.ResponseBadRequest(c, "Incorrect input data")
+		return
+	}
+
+	coin, ok := resolveCoin(c, h.coinRegistry, req.Coin)
+	if !ok {
+		return
+	}
+	req.Coin = coin
+
+	rule, err := alertRule(req)
+	if err != nil {
+		zap.L().Error("Invalid alert rule", zap.Error(err), zap.String("name:", req.Coin))
+		common.ResponseBadRequest(c, err.Error())
+		return
+	}
+
+	created, err := h.alertService.Create(ctx, rule)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidAlert) {
+			zap.L().Error("Invalid alert rule", zap.Error(err), zap.String("name:", rule.Coin))
+			common.ResponseBadRequest(c, err.Error())
+			return
+		}
+		zap.L().Error("Create alert error", zap.Error(err), zap.String("name:", rule.Coin))
+		common.ResponseServerError(c, "Service error while creating alert")
+		return
+	}
+	common.ResponseSuccess(c, "Alert created", created)
+
+	zap.L().Info("Successful alert creation", zap.Int64("id:", created.ID))
+}
+
+// ListAlerts обрабатывает запрос на получение всех правил оповещений (без секретов).
+//
+// Маршрут: GET /api/v1/alerts
+//
+// Возможные ответы:
+//   - 200 OK: правила от новых к старым.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	rules, err := h.alertService.List(ctx)
+	if err != nil {
+		zap.L().Error("List alerts error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, rules)
+}
+
+// GetAlert обрабатывает запрос на получение правила оповещения (без секрета).
+//
+// Маршрут: GET /api/v1/alerts/{id}
+//
+// Возможные ответы:
+//   - 200 OK: правило и его состояние.
+//   - 400 Bad Request: некорректный id.
+//   - 404 Not Found: правило не найдено.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, ok := alertID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alertService.Get(ctx, id)
+	if err != nil {
+		respondAlertError(c, err, id)
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, rule)
+}
+
+// DeleteAlert обрабатывает запрос на удаление правила вместе с журналом доставки.
+//
+// Маршрут: DELETE /api/v1/alerts/{id}
+//
+// Возможные ответы:
+//   - 200 OK: правило удалено.
+//   - 400 Bad Request: некорректный id.
+//   - 404 Not Found: правило не найдено.
+//   - 500 Internal Server Error: ошибка сервиса при удалении.
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, ok := alertID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.alertService.Delete(ctx, id); err != nil {
+		respondAlertError(c, err, id)
+		return
+	}
+	common.ResponseSuccess(c, "Alert deleted", nil)
+}
+
+// GetDeliveries обрабатывает запрос на получение журнала доставки правила.
+//
+// Маршрут: GET /api/v1/alerts/{id}/deliveries
+//
+// Параметры запроса (query):
+//   - limit: число записей (int, по умолчанию 100, максимум 1000)
+//
+// Возможные ответы:
+//   - 200 OK: оповещения от новых к старым со статусом, числом попыток и последней ошибкой.
+//   - 400 Bad Request: некорректный id или limit.
+//   - 404 Not Found: правило не найдено.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *AlertHandler) GetDeliveries(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, ok := alertID(c)
+	if !ok {
+		return
+	}
+
+	limit := common.DefaultDeliveryLimit
+	if raw := c.Query("limit"); raw != common.Empty {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= common.Zero || value > common.MaxDeliveryLimit {
+			common.ResponseBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = value
+	}
+
+	deliveries, err := h.alertService.Deliveries(ctx, id, limit)
+	if err != nil {
+		respondAlertError(c, err, id)
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, deliveries)
+}
+
+// alertRule проверяет запрос и собирает из него правило. Ошибки проверки оборачивают ErrInvalidAlert.
+func alertRule(req *models.AlertRequest) (*models.AlertRule, error) {
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unsupported currency", common.ErrInvalidAlert)
+	}
+
+	threshold, err := decimal.NewFromString(req.Threshold)
+	if err != nil || !threshold.IsPositive() {
+		return nil, fmt.Errorf("%w: invalid threshold", common.ErrInvalidAlert)
+	}
+
+	window := req.Window
+	switch req.Condition {
+	case common.AlertAbove, common.AlertBelow:
+		window = common.Zero
+	case common.AlertChange:
+		if window <= common.Zero || window > common.MaxAlertWindow {
+			return nil, fmt.Errorf("%w: invalid window", common.ErrInvalidAlert)
+		}
+	default:
+		return nil, fmt.Errorf("%w: invalid condition", common.ErrInvalidAlert)
+	}
+
+	webhook, err := url.Parse(req.WebhookURL)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == common.Empty {
+		return nil, fmt.Errorf("%w: invalid webhook_url", common.ErrInvalidAlert)
+	}
+
+	return &models.AlertRule{
+		Coin:       req.Coin,
+		Currency:   currency,
+		Condition:  req.Condition,
+		Threshold:  models.NewDecimal(threshold),
+		Window:     window,
+		WebhookURL: webhook.String(),
+		Secret:     req.Secret,
+	}, nil
+}
+
+// respondAlertError отвечает на ошибку сервиса оповещений.
+func respondAlertError(c *gin.Context, err error, id int64) {
+	switch {
+	case errors.Is(err, common.ErrAlertNotFound):
+		common.ResponseNotFound(c, "Alert not found")
+	default:
+		zap.L().Error("Alert service error", zap.Error(err), zap.Int64("id:", id))
+		common.ResponseServerError(c, "Error while receiving data")
+	}
+}
+
+// alertID разбирает id правила из пути и отвечает 400, если он некорректен.
+func alertID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= common.Zero {
+		common.ResponseBadRequest(c, "Invalid alert id")
+		return common.Zero, false
+	}
+	return id, true
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AnalyticsHandler обрабатывает запросы аналитики по нескольким монетам.
+type AnalyticsHandler struct {
+	analyticsService interfaces.AnalyticsServiceI
+	coinRegistry     interfaces.CoinRegistryI
+}
+
+func NewAnalyticsHandler(analyticsService interfaces.AnalyticsServiceI, coinRegistry interfaces.CoinRegistryI) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		coinRegistry:     coinRegistry,
+	}
+}
+
+// GetCorrelation обрабатывает запрос матриц корреляции и ковариации доходностей монет.
+//
+// Маршрут: GET /api/v1/analytics/correlation
+//
+// Параметры запроса (query):
+//   - coins: монеты через запятую (ID, тикер, название или псевдоним), от 2 до 20
+//   - interval: интервал доходности, как у свечей (по умолчанию 1d)
+//   - from: начало периода, unix-время (int, по умолчанию to - 90 дней)
+//   - to: конец периода, unix-время (int, по умолчанию текущее время)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - returns: log или simple (string, по умолчанию log)
+//
+// Возможные ответы:
+//   - 200 OK: матрицы Пирсона, Спирмена и ковариации в порядке coins и число общих наблюдений.
+//   - 400 Bad Request: некорректные входные данные или слишком много интервалов.
+//   - 404 Not Found: недостаточно интервалов, в которых есть доходность каждой монеты.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *AnalyticsHandler) GetCorrelation(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start computing correlation...")
+
+	req := new(models.CorrelationRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, value := range strings.Split(req.Coins, ",") {
+		if strings.TrimSpace(value) == common.Empty {
+			continue
+		}
+		coin, err := lookupCoin(h.coinRegistry, value)
+		if err != nil {
+			respondCoinError(c, err)
+			return
+		}
+		if !seen[coin] {
+			seen[coin] = true
+			req.CoinList = append(req.CoinList, coin)
+		}
+	}
+	if len(req.CoinList) < 2 || len(req.CoinList) > common.MaxCorrelationCoins {
+		common.ResponseBadRequest(c, fmt.Sprintf("Between 2 and %d distinct coins are required", common.MaxCorrelationCoins))
+		return
+	}
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		zap.L().Error("Unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	req.Currency = currency
+
+	if req.Returns == common.Empty {
+		req.Returns = common.ReturnsLog
+	}
+	if req.Returns != common.ReturnsLog && req.Returns != common.ReturnsSimple {
+		common.ResponseBadRequest(c, "Invalid returns, expected log or simple")
+		return
+	}
+
+	if req.Interval == common.Empty {
+		req.Interval = common.DefaultCorrelationInterval
+	}
+	step, err := common.ParseInterval(req.Interval)
+	if err != nil {
+		zap.L().Error("GetCorrelation invalid interval", zap.String("interval:", req.Interval))
+		common.ResponseBadRequest(c, "Invalid interval")
+		return
+	}
+	req.Step = step
+
+	if req.To == common.Zero {
+		req.To = time.Now().Unix()
+	}
+	if req.From == common.Zero {
+		req.From = req.To - common.DefaultCorrelationWindow
+	}
+
+	if req.From < common.Zero || req.From > req.To {
+		zap.L().Error("GetCorrelation invalid range", zap.Int64("from:", req.From), zap.Int64("to:", req.To))
+		common.ResponseBadRequest(c, "Invalid time range")
+		return
+	}
+	// Первый интервал начинается с границы до from, его тоже нужно учесть в лимите.
+	start := req.From - req.From%req.Step
+	if (req.To-start)/req.Step+1 > common.MaxCandles {
+		common.ResponseBadRequest(c, fmt.Sprintf("Too many intervals requested, maximum is %d", common.MaxCandles))
+		return
+	}
+
+	data, err := h.analyticsService.Correlation(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrNotEnoughData):
+			common.ResponseNotFound(c, "Not enough overlapping price data for the chosen coins and window")
+		default:
+			zap.L().Error("DB error", zap.Error(err))
+			common.ResponseServerError(c, "Error while receiving data")
+		}
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful correlation")
+}
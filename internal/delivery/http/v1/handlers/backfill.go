@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BackfillHandler обрабатывает административные запросы на дозагрузку истории цен.
+type BackfillHandler struct {
+	backfiller   interfaces.BackfillerI
+	coinRegistry interfaces.CoinRegistryI
+}
+
+func NewBackfillHandler(backfiller interfaces.BackfillerI, coinRegistry interfaces.CoinRegistryI) *BackfillHandler {
+	return &BackfillHandler{
+		backfiller:   backfiller,
+		coinRegistry: coinRegistry,
+	}
+}
+
+// CreateBackfill обрабатывает запрос на постановку задачи дозагрузки истории в очередь.
+//
+// Маршрут: POST /api/v1/admin/backfill
+//
+// Параметры запроса (JSON):
+//   - coin: ID, тикер, название или псевдоним монеты (string)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - from: начало периода (int, по умолчанию to минус 30 дней)
+//   - to: конец периода (int, по умолчанию текущее время)
+//
+// Возможные ответы:
+//   - 202 Accepted: задача создана или уже выполняется с теми же параметрами.
+//   - 400 Bad Request: некорректные входные данные или монета не найдена.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса или нет источника истории.
+func (h *BackfillHandler) CreateBackfill(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start creating backfill...")
+
+	req := new(models.BackfillRequest)
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		zap.L().Error("ShouldBindJSON error", zap.Error(err))
+		common.ResponseBadRequest(c, "Incorrect input data")
+		return
+	}
+
+	coin, ok := resolveCoin(c, h.coinRegistry, req.Coin)
+	if !ok {
+		return
+	}
+	req.Coin = coin
+
+	job, err := h.backfiller.Enqueue(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidRange):
+			common.ResponseBadRequest(c, "Invalid time range")
+		case errors.Is(err, common.ErrUnsupportedCurrency):
+			common.ResponseBadRequest(c, "Unsupported currency")
+		default:
+			zap.L().Error("Enqueue backfill error", zap.Error(err), zap.String("name:", req.Coin))
+			common.ResponseServerError(c, "Service error while creating backfill")
+		}
+		return
+	}
+	common.ResponseAccepted(c, "Backfill queued", job)
+
+	zap.L().Info("Successful backfill creation", zap.Int64("id:", job.ID))
+}
+
+// ListBackfills обрабатывает запрос на получение последних задач дозагрузки.
+//
+// Маршрут: GET /api/v1/admin/backfill
+//
+// Параметры запроса (query):
+//   - limit: число задач (int, по умолчанию 50, максимум 500)
+//
+// Возможные ответы:
+//   - 200 OK: задачи от новых к старым.
+//   - 400 Bad Request: некорректный limit.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *BackfillHandler) ListBackfills(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	limit := common.DefaultBackfillLimit
+	if raw := c.Query("limit"); raw != common.Empty {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= common.Zero || value > common.MaxBackfillLimit {
+			common.ResponseBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = value
+	}
+
+	jobs, err := h.backfiller.List(ctx, limit)
+	if err != nil {
+		zap.L().Error("List backfills error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, jobs)
+}
+
+// GetBackfill обрабатывает запрос на получение состояния задачи дозагрузки.
+//
+// Маршрут: GET /api/v1/admin/backfill/{id}
+//
+// Возможные ответы:
+//   - 200 OK: состояние и прогресс задачи.
+//   - 400 Bad Request: некорректный id.
+//   - 404 Not Found: задача не найдена.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *BackfillHandler) GetBackfill(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, ok := backfillID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.backfiller.Get(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrBackfillNotFound):
+			common.ResponseNotFound(c, "Backfill not found")
+		default:
+			zap.L().Error("Get backfill error", zap.Error(err), zap.Int64("id:", id))
+			common.ResponseServerError(c, "Error while receiving data")
+		}
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, job)
+}
+
+// RetryBackfill обрабатывает запрос на повтор задачи, завершившейся ошибкой.
+// Загрузка продолжается с сохранённого курсора.
+//
+// Маршрут: POST /api/v1/admin/backfill/{id}/retry
+//
+// Возможные ответы:
+//   - 202 Accepted: задача возвращена в очередь.
+//   - 400 Bad Request: некорректный id или задача не в статусе failed.
+//   - 404 Not Found: задача не найдена.
+//   - 500 Internal Server Error: ошибка сервиса.
+func (h *BackfillHandler) RetryBackfill(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, ok := backfillID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.backfiller.Retry(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrBackfillNotFound):
+			common.ResponseNotFound(c, "Backfill not found")
+		case errors.Is(err, common.ErrBackfillNotClaimed):
+			common.ResponseBadRequest(c, "Only failed backfills can be retried")
+		default:
+			zap.L().Error("Retry backfill error", zap.Error(err), zap.Int64("id:", id))
+			common.ResponseServerError(c, "Service error while retrying backfill")
+		}
+		return
+	}
+	common.ResponseAccepted(c, "Backfill queued", job)
+}
+
+// backfillID разбирает id задачи из пути и отвечает 400, если он некорректен.
+func backfillID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= common.Zero {
+		common.ResponseBadRequest(c, "Invalid backfill id")
+		return common.Zero, false
+	}
+	return id, true
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CoinHandler обрабатывает запросы к реестру валидных монет.
+type CoinHandler struct {
+	coinRegistry interfaces.CoinRegistryI
+}
+
+func NewCoinHandler(coinRegistry interfaces.CoinRegistryI) *CoinHandler {
+	return &CoinHandler{
+		coinRegistry: coinRegistry,
+	}
+}
+
+// SearchCoins обрабатывает запрос на поиск монет для автодополнения.
+//
+// Маршрут: GET /api/v1/coins/search
+//
+// Параметры запроса (query):
+//   - q: строка поиска по ID, тикеру, названию и псевдонимам (string, до 64 символов)
+//   - limit: число результатов (int, по умолчанию 10, максимум 50)
+//
+// Возможные ответы:
+//   - 200 OK: монеты по убыванию качества совпадения (exact, prefix, substring, fuzzy), затем по капитализации.
+//   - 400 Bad Request: пустой или слишком длинный запрос, некорректный limit.
+func (h *CoinHandler) SearchCoins(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == common.Empty || len([]rune(query)) > common.MaxSearchQuery {
+		common.ResponseBadRequest(c, fmt.Sprintf("Query length must be between 1 and %d", common.MaxSearchQuery))
+		return
+	}
+
+	limit := common.DefaultSearchLimit
+	if raw := c.Query("limit"); raw != common.Empty {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= common.Zero || value > common.MaxSearchLimit {
+			common.ResponseBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = value
+	}
+
+	common.ResponseSuccess(c, common.Empty, h.coinRegistry.Search(query, limit))
+}
+
+// resolveCoin определяет ID монеты по обозначению (ID, псевдоним, тикер или название) для операций,
+// которым нужна монета из реестра. При ошибке отвечает 400 (монета не найдена) или 409 (неоднозначно).
+func resolveCoin(c *gin.Context, coinRegistry interfaces.CoinRegistryI, coin string) (string, bool) {
+	info, err := coinRegistry.Resolve(coin)
+	if err != nil {
+		zap.L().Error("Resolve coin error", zap.Error(err), zap.String("name:", coin))
+		respondCoinError(c, err)
+		return common.Empty, false
+	}
+	return info.ID, true
+}
+
+// lookupCoin определяет ID монеты для чтения уже сохранённых данных. Обозначение, которого нет
+// в реестре, возвращается в нижнем регистре, чтобы данные монет, выбывших из реестра, оставались
+// доступны; ошибкой считается только неоднозначное обозначение.
+func lookupCoin(coinRegistry interfaces.CoinRegistryI, coin string) (string, error) {
+	info, err := coinRegistry.Resolve(coin)
+	switch {
+	case err == nil:
+		return info.ID, nil
+	case errors.Is(err, common.ErrCoinNotFound):
+		return strings.ToLower(strings.TrimSpace(coin)), nil
+	default:
+		return common.Empty, err
+	}
+}
+
+// respondCoinError отвечает на ошибку определения монеты; для неоднозначного обозначения
+// в data возвращаются подходящие монеты.
+func respondCoinError(c *gin.Context, err error) {
+	var ambiguous *common.AmbiguousCoinError
+	switch {
+	case errors.As(err, &ambiguous):
+		common.ResponseConflict(c, "Ambiguous coin, specify the coin ID", ambiguous)
+	default:
+		common.ResponseBadRequest(c, "Coin not found")
+	}
+}
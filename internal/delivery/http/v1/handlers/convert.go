@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ConvertHandler обрабатывает запросы на пересчёт сумм между монетами и валютами.
+type ConvertHandler struct {
+	converter    interfaces.ConverterI
+	coinRegistry interfaces.CoinRegistryI
+}
+
+func NewConvertHandler(converter interfaces.ConverterI, coinRegistry interfaces.CoinRegistryI) *ConvertHandler {
+	return &ConvertHandler{
+		converter:    converter,
+		coinRegistry: coinRegistry,
+	}
+}
+
+// Convert обрабатывает запрос на пересчёт суммы по сохранённым ценам.
+//
+// Маршрут: GET /api/v1/convert
+//
+// Параметры запроса (query):
+//   - from: монета (ID, тикер, название или псевдоним) или код валюты (string)
+//   - to: монета (ID, тикер, название или псевдоним) или код валюты (string)
+//   - amount: сумма (десятичная строка, по умолчанию 1)
+//   - timestamp: метка времени (int, по умолчанию текущее время)
+//
+// Возможные ответы:
+//   - 200 OK: курс, результат, использованные сэмплы и их разброс по времени.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 404 Not Found: нет маршрута пересчёта по сохранённым котировкам или нет цен ни для одного маршрута.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *ConvertHandler) Convert(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start converting...")
+
+	req := new(models.ConvertRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if req.From == common.Empty || req.To == common.Empty || req.Timestamp < common.Zero {
+		zap.L().Error("Convert invalid request", zap.String("from:", req.From), zap.String("to:", req.To))
+		common.ResponseBadRequest(c, "Required fields: from and to")
+		return
+	}
+
+	// Код валюты котировки имеет приоритет: BTC — это валюта, а не тикер монеты.
+	for _, value := range []*string{&req.From, &req.To} {
+		if _, err := common.NormalizeCurrency(*value); err == nil {
+			continue
+		}
+		coin, err := lookupCoin(h.coinRegistry, *value)
+		if err != nil {
+			respondCoinError(c, err)
+			return
+		}
+		*value = coin
+	}
+
+	req.Value = decimal.NewFromInt(1)
+	if req.Amount != common.Empty {
+		amount, err := decimal.NewFromString(req.Amount)
+		if err != nil || amount.IsNegative() {
+			zap.L().Error("Convert invalid amount", zap.String("amount:", req.Amount))
+			common.ResponseBadRequest(c, "Invalid amount")
+			return
+		}
+		req.Value = amount
+	}
+	if req.Timestamp == common.Zero {
+		req.Timestamp = time.Now().Unix()
+	}
+
+	data, err := h.converter.Convert(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrNoConversionRoute):
+			common.ResponseNotFound(c, "No conversion route over stored prices")
+		case errors.Is(err, common.ErrPriceNotFound):
+			common.ResponseNotFound(c, "Price not found")
+		default:
+			zap.L().Error("DB error", zap.Error(err))
+			common.ResponseServerError(c, "Error while receiving data")
+		}
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful convert")
+}
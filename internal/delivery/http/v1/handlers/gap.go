@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GapHandler обрабатывает запросы о пропусках в истории цен.
+type GapHandler struct {
+	gapDetector  interfaces.GapDetectorI
+	coinRegistry interfaces.CoinRegistryI
+}
+
+func NewGapHandler(gapDetector interfaces.GapDetectorI, coinRegistry interfaces.CoinRegistryI) *GapHandler {
+	return &GapHandler{
+		gapDetector:  gapDetector,
+		coinRegistry: coinRegistry,
+	}
+}
+
+// gapStatuses — допустимые значения фильтра status.
+var gapStatuses = map[string]bool{
+	common.GapOpen:         true,
+	common.GapRepairing:    true,
+	common.GapRepaired:     true,
+	common.GapUnrepairable: true,
+	common.GapFailed:       true,
+}
+
+// GetGaps обрабатывает запрос на получение найденных пропусков в истории цен.
+//
+// Маршрут: GET /api/v1/gaps
+//
+// Параметры запроса (query):
+//   - coin: ID, тикер, название или псевдоним монеты (string, необязательно)
+//   - currency: валюта котировки (string, необязательно)
+//   - status: open, repairing, repaired, unrepairable или failed (string, необязательно)
+//   - limit: число пропусков (int, по умолчанию 100, максимум 1000)
+//
+// Возможные ответы:
+//   - 200 OK: пропуски от новых к старым.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *GapHandler) GetGaps(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	req := new(models.GapRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if req.Coin != common.Empty {
+		coin, err := lookupCoin(h.coinRegistry, req.Coin)
+		if err != nil {
+			respondCoinError(c, err)
+			return
+		}
+		req.Coin = coin
+	}
+	if req.Currency != common.Empty {
+		currency, err := common.NormalizeCurrency(req.Currency)
+		if err != nil {
+			common.ResponseBadRequest(c, "Unsupported currency")
+			return
+		}
+		req.Currency = currency
+	}
+	if req.Status != common.Empty && !gapStatuses[req.Status] {
+		common.ResponseBadRequest(c, "Invalid status")
+		return
+	}
+	if req.Limit == common.Zero {
+		req.Limit = common.DefaultGapLimit
+	}
+	if req.Limit < common.Zero || req.Limit > common.MaxGapLimit {
+		common.ResponseBadRequest(c, "Invalid limit")
+		return
+	}
+
+	gaps, err := h.gapDetector.List(ctx, req)
+	if err != nil {
+		zap.L().Error("List gaps error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, gaps)
+}
+
+// RepairGap обрабатывает запрос на заполнение пропуска дозагрузкой истории.
+//
+// Маршрут: POST /api/v1/admin/gaps/{id}/repair
+//
+// Возможные ответы:
+//   - 202 Accepted: задача дозагрузки создана, пропуск в статусе repairing.
+//   - 400 Bad Request: некорректный id или пропуск уже заполнен или заполняется.
+//   - 404 Not Found: пропуск не найден.
+//   - 500 Internal Server Error: ошибка сервиса или нет источника истории.
+func (h *GapHandler) RepairGap(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= common.Zero {
+		common.ResponseBadRequest(c, "Invalid gap id")
+		return
+	}
+
+	gap, err := h.gapDetector.Repair(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrGapNotFound):
+			common.ResponseNotFound(c, "Gap not found")
+		case errors.Is(err, common.ErrGapNotRepairable):
+			common.ResponseBadRequest(c, "Gap is already repaired or being repaired")
+		default:
+			zap.L().Error("Repair gap error", zap.Error(err), zap.Int64("id:", id))
+			common.ResponseServerError(c, "Service error while repairing gap")
+		}
+		return
+	}
+	common.ResponseAccepted(c, "Gap repair queued", gap)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IndicatorHandler обрабатывает запросы технических индикаторов по сохранённой истории цен.
+type IndicatorHandler struct {
+	indicatorService interfaces.IndicatorServiceI
+	coinRegistry     interfaces.CoinRegistryI
+}
+
+func NewIndicatorHandler(indicatorService interfaces.IndicatorServiceI, coinRegistry interfaces.CoinRegistryI) *IndicatorHandler {
+	return &IndicatorHandler{
+		indicatorService: indicatorService,
+		coinRegistry:     coinRegistry,
+	}
+}
+
+// GetIndicators обрабатывает запрос на расчёт технических индикаторов монеты за период.
+//
+// Маршрут: GET /api/v1/currency/{coin}/indicators
+//
+// Параметры запроса (query):
+//   - indicators: список через запятую: sma[:period], ema[:period], rsi[:period], macd[:fast:slow:signal],
+//     bb[:period:k], vol[:period] (string, по умолчанию все с параметрами по умолчанию, не больше 10)
+//   - interval: интервал свечи, по ценам закрытия которых считаются индикаторы (по умолчанию 1h)
+//   - from: начало периода, unix-время (int, по умолчанию to - 7 дней)
+//   - to: конец периода, unix-время (int, по умолчанию текущее время)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//
+// Возможные ответы:
+//   - 200 OK: цены закрытия и значения индикаторов по интервалам (null, пока индикатору не хватает истории).
+//   - 400 Bad Request: некорректные входные данные, неизвестный индикатор или слишком много свечей.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *IndicatorHandler) GetIndicators(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start computing indicators for coin...")
+
+	req := new(models.IndicatorRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	coin, err := lookupCoin(h.coinRegistry, c.Param("coin"))
+	if err != nil {
+		respondCoinError(c, err)
+		return
+	}
+	req.Coin = coin
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		zap.L().Error("Unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	req.Currency = currency
+
+	if req.Interval == common.Empty {
+		req.Interval = common.DefaultCandleInterval
+	}
+	step, err := common.ParseInterval(req.Interval)
+	if err != nil {
+		zap.L().Error("GetIndicators invalid interval", zap.String("interval:", req.Interval))
+		common.ResponseBadRequest(c, "Invalid interval")
+		return
+	}
+	req.Step = step
+
+	if req.To == common.Zero {
+		req.To = time.Now().Unix()
+	}
+	if req.From == common.Zero {
+		req.From = req.To - common.DefaultIndicatorWindow
+	}
+
+	if req.Coin == common.Empty || req.From < common.Zero || req.From > req.To {
+		zap.L().Error("GetIndicators invalid range", zap.String("coin:", req.Coin), zap.Int64("from:", req.From), zap.Int64("to:", req.To))
+		common.ResponseBadRequest(c, "Invalid coin or time range")
+		return
+	}
+
+	data, err := h.indicatorService.Compute(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidIndicator), errors.Is(err, common.ErrInvalidRange):
+			common.ResponseBadRequest(c, err.Error())
+		default:
+			zap.L().Error("DB error", zap.Error(err))
+			common.ResponseServerError(c, "Error while receiving data")
+		}
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful coin getIndicators")
+}
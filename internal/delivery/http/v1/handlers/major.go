@@ -8,20 +8,31 @@ import (
 	"testYTask/internal/common"
 	"testYTask/internal/domain/interfaces"
 	"testYTask/internal/domain/models"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// priceModes — допустимые режимы выбора цены на момент времени.
+var priceModes = map[string]bool{
+	common.ModeNearest: true,
+	common.ModeBefore:  true,
+	common.ModeAfter:   true,
+	common.ModeLinear:  true,
+}
+
 type MajorHandler struct {
 	majorRepository interfaces.MajorRepositoryI
-	mapCoins        map[string]bool
+	backfiller      interfaces.BackfillerI
+	coinRegistry    interfaces.CoinRegistryI
 }
 
-func NewMajorHandler(majorRepository interfaces.MajorRepositoryI, mapCoins map[string]bool) *MajorHandler {
+func NewMajorHandler(majorRepository interfaces.MajorRepositoryI, backfiller interfaces.BackfillerI, coinRegistry interfaces.CoinRegistryI) *MajorHandler {
 	return &MajorHandler{
 		majorRepository: majorRepository,
-		mapCoins:        mapCoins,
+		backfiller:      backfiller,
+		coinRegistry:    coinRegistry,
 	}
 }
 
@@ -30,12 +41,16 @@ func NewMajorHandler(majorRepository interfaces.MajorRepositoryI, mapCoins map[s
 // Маршрут: POST /api/v1/currency/add
 //
 // Параметры запроса (JSON):
-//   - name_coin: название монеты (string)
+//   - name_coin: ID, тикер, название или псевдоним монеты (string)
+//   - currencies: валюты котировки ([]string, по умолчанию ["USD"]); при повторном добавлении объединяются с уже отслеживаемыми,
+//     всего не больше MaxCurrenciesPerCoin
+//
+// Для каждой валюты ставится задача дозагрузки истории за последние 30 дней.
 //
 // Возможные ответы:
 //   - 200 OK: монета успешно добавлена.
-//   - 400 Bad Request: некорректные входные данные.
-//   - 404 Not Found: монета не найдена.
+//   - 400 Bad Request: некорректные входные данные, монета не найдена или превышен лимит валют монеты.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
 //   - 500 Internal Server Error: ошибка сервиса при добавлении.
 func (h *MajorHandler) AddingCoin(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
@@ -51,33 +66,49 @@ func (h *MajorHandler) AddingCoin(c *gin.Context) {
 		return
 	}
 
-	if !h.mapCoins[strings.ToLower(coin.NameCoin)] {
-		zap.L().Error("Map coin not found", zap.String("name:", coin.NameCoin))
-		common.ResponseBadRequest(c, "Coin not found")
+	id, ok := resolveCoin(c, h.coinRegistry, coin.NameCoin)
+	if !ok {
 		return
 	}
+	coin.NameCoin = id
+
+	currencies, err := common.NormalizeCurrencies(coin.Currencies)
+	if err != nil || len(currencies) > common.MaxCurrenciesPerCoin {
+		zap.L().Error("AddingCoin invalid currencies", zap.Error(err), zap.Strings("currencies:", coin.Currencies))
+		common.ResponseBadRequest(c, "Unsupported or too many currencies")
+		return
+	}
+	coin.Currencies = currencies
 
 	if err := h.majorRepository.AddingNewCoin(ctx, coin); err != nil {
+		if errors.Is(err, common.ErrTooManyCurrencies) {
+			zap.L().Info("AddingCoin currency limit reached", zap.String("name:", coin.NameCoin), zap.Strings("currencies:", coin.Currencies))
+			common.ResponseBadRequest(c, fmt.Sprintf("A coin can be tracked in at most %d currencies", common.MaxCurrenciesPerCoin))
+			return
+		}
 		zap.L().Error("AddingNewCoin error", zap.Error(err), zap.String("name:", coin.NameCoin))
 		common.ResponseServerError(c, "Service error while adding")
 		return
 	}
+	h.enqueueBackfill(ctx, coin)
 	common.ResponseSuccess(c, fmt.Sprintf("Coin '%s' added", coin.NameCoin), struct{}{})
 
 	zap.L().Info("Successful coin addition")
 }
 
-// DeleteCoin обрабатывает запрос на удаление монеты.
+// DeleteCoin обрабатывает запрос на удаление монеты или отдельных её валют.
 //
 // Маршрут: DELETE /api/v1/currency/remove
 //
 // Параметры запроса (JSON):
-//   - name_coin: название монеты (string)
+//   - name_coin: ID, тикер, название или псевдоним монеты (string)
+//   - currencies: валюты, отслеживание в которых прекращается ([]string, необязательно); без них монета удаляется целиком,
+//     как и при удалении всех оставшихся валют
 //
 // Возможные ответы:
-//   - 200 OK: монета успешно удалена.
-//   - 400 Bad Request: некорректные входные данные.
-//   - 404 Not Found: монета отсутствует в списке.
+//   - 200 OK: монета или её валюты успешно удалены.
+//   - 400 Bad Request: некорректные входные данные или монета не отслеживается (в указанных валютах).
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
 //   - 500 Internal Server Error: ошибка сервиса при удалении.
 func (h *MajorHandler) DeleteCoin(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
@@ -93,6 +124,18 @@ func (h *MajorHandler) DeleteCoin(c *gin.Context) {
 		return
 	}
 
+	id, err := lookupCoin(h.coinRegistry, coin.NameCoin)
+	if err != nil {
+		respondCoinError(c, err)
+		return
+	}
+	coin.NameCoin = id
+
+	if len(coin.Currencies) > common.Zero {
+		h.removeCurrencies(ctx, c, coin)
+		return
+	}
+
 	if err := h.majorRepository.DeleteCoin(ctx, coin); err != nil {
 		switch {
 		case errors.Is(err, common.ErrCoinNotFound):
@@ -109,18 +152,47 @@ func (h *MajorHandler) DeleteCoin(c *gin.Context) {
 	zap.L().Info("Successful coin deletion")
 }
 
+// removeCurrencies прекращает отслеживание монеты в валютах из запроса.
+func (h *MajorHandler) removeCurrencies(ctx context.Context, c *gin.Context, coin *models.Coin) {
+	currencies, err := common.NormalizeCurrencies(coin.Currencies)
+	if err != nil || len(currencies) > common.MaxCurrenciesPerCoin {
+		zap.L().Error("DeleteCoin invalid currencies", zap.Error(err), zap.Strings("currencies:", coin.Currencies))
+		common.ResponseBadRequest(c, "Unsupported or too many currencies")
+		return
+	}
+	coin.Currencies = currencies
+
+	if err := h.majorRepository.RemoveCurrencies(ctx, coin); err != nil {
+		switch {
+		case errors.Is(err, common.ErrCoinNotFound):
+			common.ResponseBadRequest(c, "This coin is not tracked in these currencies")
+		default:
+			zap.L().Error("RemoveCurrencies error", zap.Error(err), zap.String("name:", coin.NameCoin))
+			common.ResponseServerError(c, "Service error while deleting")
+		}
+		return
+	}
+	common.ResponseSuccess(c, "Currencies removed", struct{}{})
+
+	zap.L().Info("Successful coin currencies removal")
+}
+
 // GetPriceForCoin обрабатывает запрос на получение цены указанной монеты.
 //
 // Маршрут: POST /api/v1/currency/price
 //
 // Параметры запроса (JSON):
-//   - coin: название монеты (string)
+//   - coin: ID, тикер, название или псевдоним монеты (string)
 //   - timestamp: метка времени (int)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - mode: nearest, before, after или linear (string, по умолчанию nearest)
+//   - max_distance: наибольшее допустимое расстояние до сэмпла в секундах (int, 0 — без ограничения)
 //
 // Возможные ответы:
 //   - 200 OK: цена успешно получена.
 //   - 400 Bad Request: некорректные входные данные.
-//   - 404 Not Found: цена не найдена.
+//   - 404 Not Found: цена не найдена или ближайший сэмпл дальше max_distance.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
 //   - 500 Internal Server Error: ошибка сервиса при получении данных.
 func (h *MajorHandler) GetPriceForCoin(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
@@ -136,11 +208,18 @@ func (h *MajorHandler) GetPriceForCoin(c *gin.Context) {
 		return
 	}
 
-	if req.Coin == common.Empty || req.Timestamp <= common.Zero {
-		zap.L().Error("GetPriceForCoin invalid coin or timestamp", zap.String("coin:", req.Coin), zap.Int64("Time:", req.Timestamp))
-		common.ResponseBadRequest(c, "Required fields: coin and timestamp")
+	if msg, ok := preparePriceRequest(req); !ok {
+		zap.L().Error("GetPriceForCoin invalid request", zap.String("coin:", req.Coin), zap.Int64("Time:", req.Timestamp), zap.String("reason:", msg))
+		common.ResponseBadRequest(c, msg)
+		return
+	}
+
+	coin, err := lookupCoin(h.coinRegistry, req.Coin)
+	if err != nil {
+		respondCoinError(c, err)
 		return
 	}
+	req.Coin = coin
 
 	data, err := h.majorRepository.GetPrice(ctx, req)
 	if err != nil {
@@ -148,6 +227,10 @@ func (h *MajorHandler) GetPriceForCoin(c *gin.Context) {
 			zap.L().Info("GetPriceForCoin not found", zap.String("coin:", req.Coin))
 			common.ResponseNotFound(c, "Price not found")
 			return
+		} else if errors.Is(err, common.ErrPriceTooFar) {
+			zap.L().Info("GetPriceForCoin sample too far", zap.String("coin:", req.Coin), zap.Int64("max_distance:", req.MaxDistance))
+			common.ResponseNotFound(c, "No price sample within max_distance")
+			return
 		} else {
 			zap.L().Error("DB error", zap.Error(err))
 			common.ResponseServerError(c, "Error while receiving data")
@@ -158,3 +241,439 @@ func (h *MajorHandler) GetPriceForCoin(c *gin.Context) {
 
 	zap.L().Info("Successful coin getPrice")
 }
+
+// GetPricesBatch обрабатывает пакетный запрос цен для множества пар (монета, метка времени).
+//
+// Маршрут: POST /api/v1/currency/price/batch
+//
+// Параметры запроса (JSON):
+//   - items: массив запросов в формате POST /currency/price (coin, timestamp, currency, mode, max_distance), до 1000 элементов
+//
+// Все корректные элементы обрабатываются одним запросом к базе. Ошибка элемента не прерывает пакет:
+// для каждого элемента возвращается собственный status (OK, NotFound, ERROR) и message.
+//
+// Возможные ответы:
+//   - 200 OK: результаты по всем элементам в порядке запроса.
+//   - 400 Bad Request: некорректный JSON, пустой или слишком большой пакет.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MajorHandler) GetPricesBatch(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start getting batch prices...")
+
+	req := new(models.BatchPriceRequest)
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		zap.L().Error("ShouldBindJSON error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if len(req.Items) == common.Zero || len(req.Items) > common.MaxBatchPrices {
+		zap.L().Error("GetPricesBatch invalid size", zap.Int("items:", len(req.Items)))
+		common.ResponseBadRequest(c, fmt.Sprintf("Items count must be between 1 and %d", common.MaxBatchPrices))
+		return
+	}
+
+	items := make([]*models.BatchPriceItem, len(req.Items))
+	valid := make([]*models.PriceRequest, 0, len(req.Items))
+	positions := make([]int, 0, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = &models.BatchPriceItem{Index: i}
+		if item == nil {
+			items[i].Status, items[i].Message = common.ItemError, "Invalid request"
+			continue
+		}
+		if msg, ok := preparePriceRequest(item); !ok {
+			items[i].Status, items[i].Message = common.ItemError, msg
+			continue
+		}
+		coin, err := lookupCoin(h.coinRegistry, item.Coin)
+		if err != nil {
+			items[i].Status, items[i].Message = common.ItemError, err.Error()
+			continue
+		}
+		item.Coin = coin
+		valid = append(valid, item)
+		positions = append(positions, i)
+	}
+
+	if len(valid) > common.Zero {
+		prices, errs, err := h.majorRepository.GetPrices(ctx, valid)
+		if err != nil {
+			zap.L().Error("DB error", zap.Error(err))
+			common.ResponseServerError(c, "Error while receiving data")
+			return
+		}
+
+		for j, i := range positions {
+			switch {
+			case errs[j] == nil:
+				items[i].Status, items[i].Price = common.ItemOK, prices[j]
+			case errors.Is(errs[j], common.ErrPriceTooFar):
+				items[i].Status, items[i].Message = common.ItemNotFound, "No price sample within max_distance"
+			default:
+				items[i].Status, items[i].Message = common.ItemNotFound, "Price not found"
+			}
+		}
+	}
+
+	response := &models.BatchPriceResponse{Items: items}
+	for _, item := range items {
+		if item.Status == common.ItemOK {
+			response.Found++
+		} else {
+			response.Failed++
+		}
+	}
+	common.ResponseSuccess(c, common.Empty, response)
+
+	zap.L().Info("Successful batch getPrice", zap.Int("found:", response.Found), zap.Int("failed:", response.Failed))
+}
+
+// preparePriceRequest проверяет запрос цены и заполняет значения по умолчанию (валюта, режим).
+//
+// Возвращает сообщение для ответа 400 и false, если запрос некорректен.
+func preparePriceRequest(req *models.PriceRequest) (string, bool) {
+	if req.Coin == common.Empty || req.Timestamp <= common.Zero {
+		return "Required fields: coin and timestamp", false
+	}
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return "Unsupported currency", false
+	}
+	req.Currency = currency
+
+	if req.Mode == common.Empty {
+		req.Mode = common.ModeNearest
+	}
+	if !priceModes[req.Mode] || req.MaxDistance < common.Zero {
+		return "Invalid mode or max_distance", false
+	}
+	return common.Empty, true
+}
+
+// watchedSorts — допустимые значения параметра sort списка отслеживаемых монет.
+var watchedSorts = map[string]bool{
+	common.SortCoin:          true,
+	common.SortAddedAt:       true,
+	common.SortLastTimestamp: true,
+	common.SortSamples:       true,
+	common.SortStaleness:     true,
+}
+
+// ListWatchedCoins обрабатывает запрос на получение списка отслеживаемых монет.
+//
+// Маршрут: GET /api/v1/currency
+//
+// Параметры запроса (query):
+//   - coin: подстрока ID монеты (string, необязательно)
+//   - currency: валюта котировки (string, необязательно)
+//   - stale: true — только устаревшие монеты, false — только актуальные (bool, необязательно)
+//   - stale_after: порог устарелости, сек (int, по умолчанию 90)
+//   - sort: coin, added_at, last_timestamp, samples или staleness (string, по умолчанию coin)
+//   - order: asc или desc (string, по умолчанию asc)
+//   - limit: размер страницы (int, по умолчанию 100, максимум 1000)
+//   - offset: смещение (int, по умолчанию 0)
+//
+// Возможные ответы:
+//   - 200 OK: монеты с датой добавления, последней ценой, числом цен и устарелостью по каждой валюте.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MajorHandler) ListWatchedCoins(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	req := new(models.WatchedCoinRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if req.Currency != common.Empty {
+		currency, err := common.NormalizeCurrency(req.Currency)
+		if err != nil {
+			common.ResponseBadRequest(c, "Unsupported currency")
+			return
+		}
+		req.Currency = currency
+	}
+	if req.Sort == common.Empty {
+		req.Sort = common.SortCoin
+	}
+	if req.Order == common.Empty {
+		req.Order = common.OrderAsc
+	}
+	if req.StaleAfter == common.Zero {
+		req.StaleAfter = common.DefaultStaleAfter
+	}
+	if req.Limit == common.Zero {
+		req.Limit = common.DefaultWatchedLimit
+	}
+
+	if !watchedSorts[req.Sort] || (req.Order != common.OrderAsc && req.Order != common.OrderDesc) {
+		common.ResponseBadRequest(c, "Invalid sort or order")
+		return
+	}
+	if req.StaleAfter < common.Zero || req.Offset < common.Zero || req.Limit < common.Zero || req.Limit > common.MaxWatchedLimit {
+		common.ResponseBadRequest(c, "Invalid stale_after, limit or offset")
+		return
+	}
+
+	data, err := h.majorRepository.ListWatchedCoins(ctx, req)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+}
+
+// GetPriceHistory обрабатывает запрос на получение истории цен монеты за период.
+//
+// Маршрут: GET /api/v1/currency/{coin}/history
+//
+// Параметры запроса (query):
+//   - from: начало периода, unix-время (int, по умолчанию 0)
+//   - to: конец периода, unix-время (int, по умолчанию текущее время)
+//   - limit: размер страницы (int, по умолчанию 500, максимум 5000)
+//   - cursor: значение next_cursor из предыдущего ответа (int)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//
+// Возможные ответы:
+//   - 200 OK: страница истории успешно получена.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MajorHandler) GetPriceHistory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start getting price history for coin...")
+
+	req := new(models.HistoryRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	coin, err := lookupCoin(h.coinRegistry, c.Param("coin"))
+	if err != nil {
+		respondCoinError(c, err)
+		return
+	}
+	req.Coin = coin
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		zap.L().Error("Unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	req.Currency = currency
+
+	if req.To == common.Zero {
+		req.To = time.Now().Unix()
+	}
+	if req.Limit == common.Zero {
+		req.Limit = common.DefaultHistoryLimit
+	}
+
+	if req.Coin == common.Empty || req.From < common.Zero || req.From > req.To || req.Cursor < common.Zero {
+		zap.L().Error("GetPriceHistory invalid range", zap.String("coin:", req.Coin), zap.Int64("from:", req.From), zap.Int64("to:", req.To))
+		common.ResponseBadRequest(c, "Invalid coin or time range")
+		return
+	}
+	if req.Limit < common.Zero || req.Limit > common.MaxHistoryLimit {
+		common.ResponseBadRequest(c, fmt.Sprintf("Limit must be between 1 and %d", common.MaxHistoryLimit))
+		return
+	}
+
+	data, err := h.majorRepository.GetHistory(ctx, req)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful coin getPriceHistory")
+}
+
+// GetCandles обрабатывает запрос на получение OHLC-свечей монеты за период.
+//
+// Маршрут: GET /api/v1/currency/{coin}/candles
+//
+// Параметры запроса (query):
+//   - from: начало периода, unix-время (int, по умолчанию to - 24 часа)
+//   - to: конец периода, unix-время (int, по умолчанию текущее время)
+//   - interval: интервал свечи: 1m, 5m, 15m, 30m, 1h, 4h, 1d, 1w, длительность ("90s") или число секунд (по умолчанию 1h)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//
+// Возможные ответы:
+//   - 200 OK: свечи успешно получены, пустые интервалы помечены "empty": true.
+//   - 400 Bad Request: некорректные входные данные или слишком много свечей.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MajorHandler) GetCandles(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start getting candles for coin...")
+
+	req := new(models.CandleRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	coin, err := lookupCoin(h.coinRegistry, c.Param("coin"))
+	if err != nil {
+		respondCoinError(c, err)
+		return
+	}
+	req.Coin = coin
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		zap.L().Error("Unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	req.Currency = currency
+
+	if req.Interval == common.Empty {
+		req.Interval = common.DefaultCandleInterval
+	}
+	step, err := common.ParseInterval(req.Interval)
+	if err != nil {
+		zap.L().Error("GetCandles invalid interval", zap.String("interval:", req.Interval))
+		common.ResponseBadRequest(c, "Invalid interval")
+		return
+	}
+	req.Step = step
+
+	if req.To == common.Zero {
+		req.To = time.Now().Unix()
+	}
+	if req.From == common.Zero {
+		req.From = req.To - common.DefaultCandleWindow
+	}
+
+	if req.Coin == common.Empty || req.From < common.Zero || req.From > req.To {
+		zap.L().Error("GetCandles invalid range", zap.String("coin:", req.Coin), zap.Int64("from:", req.From), zap.Int64("to:", req.To))
+		common.ResponseBadRequest(c, "Invalid coin or time range")
+		return
+	}
+	// Первая свеча начинается с границы интервала до from, её тоже нужно учесть в лимите.
+	start := req.From - req.From%req.Step
+	if (req.To-start)/req.Step+1 > common.MaxCandles {
+		common.ResponseBadRequest(c, fmt.Sprintf("Too many candles requested, maximum is %d", common.MaxCandles))
+		return
+	}
+
+	data, err := h.majorRepository.GetCandles(ctx, req)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful coin getCandles")
+}
+
+// GetAveragePrice обрабатывает запрос средневзвешенных цен монеты за окно.
+//
+// Маршрут: GET /api/v1/currency/{coin}/average
+//
+// Параметры запроса (query):
+//   - from: начало окна, unix-время (int, по умолчанию to - 24 часа)
+//   - to: конец окна, unix-время (int, по умолчанию текущее время)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - max_gap: наибольшее время действия сэмпла без следующего, сек (int, по умолчанию 90)
+//
+// Возможные ответы:
+//   - 200 OK: TWAP, VWAP, число использованных сэмплов и доля окна, покрытая сэмплами.
+//   - 400 Bad Request: некорректные входные данные или окно длиннее 366 дней.
+//   - 409 Conflict: тикер или название соответствует нескольким монетам (варианты в data).
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MajorHandler) GetAveragePrice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	zap.L().Info("Start getting average price for coin...")
+
+	req := new(models.AveragePriceRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	coin, err := lookupCoin(h.coinRegistry, c.Param("coin"))
+	if err != nil {
+		respondCoinError(c, err)
+		return
+	}
+	req.Coin = coin
+
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		zap.L().Error("Unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	req.Currency = currency
+
+	if req.To == common.Zero {
+		req.To = time.Now().Unix()
+	}
+	if req.From == common.Zero {
+		req.From = req.To - common.DefaultAverageWindow
+	}
+	if req.MaxGap == common.Zero {
+		req.MaxGap = common.DefaultAverageMaxGap
+	}
+
+	if req.Coin == common.Empty || req.From < common.Zero || req.From >= req.To || req.To-req.From > common.MaxAverageWindow || req.MaxGap < common.Zero {
+		zap.L().Error("GetAveragePrice invalid request", zap.String("coin:", req.Coin), zap.Int64("from:", req.From), zap.Int64("to:", req.To), zap.Int64("max_gap:", req.MaxGap))
+		common.ResponseBadRequest(c, "Invalid coin, time range or max_gap")
+		return
+	}
+
+	data, err := h.majorRepository.GetAveragePrice(ctx, req)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+
+	zap.L().Info("Successful coin getAveragePrice")
+}
+
+// enqueueBackfill ставит задачи дозагрузки истории для новой монеты.
+// Ошибки не прерывают добавление монеты и только записываются в лог.
+func (h *MajorHandler) enqueueBackfill(ctx context.Context, coin *models.Coin) {
+	for _, currency := range coin.Currencies {
+		job, err := h.backfiller.Enqueue(ctx, &models.BackfillRequest{
+			Coin:     strings.ToLower(coin.NameCoin),
+			Currency: currency,
+		})
+		if err != nil {
+			zap.L().Warn("Backfill not queued", zap.Error(err), zap.String("name:", coin.NameCoin), zap.String("currency:", currency))
+			continue
+		}
+		zap.L().Info("Backfill queued", zap.Int64("id:", job.ID), zap.String("name:", coin.NameCoin), zap.String("currency:", currency))
+	}
+}
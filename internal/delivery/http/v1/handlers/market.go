@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MarketHandler обрабатывает запросы обзора рынка по отслеживаемым монетам.
+type MarketHandler struct {
+	marketService interfaces.MarketServiceI
+}
+
+func NewMarketHandler(marketService interfaces.MarketServiceI) *MarketHandler {
+	return &MarketHandler{
+		marketService: marketService,
+	}
+}
+
+// GetSnapshot обрабатывает запрос снимка рынка.
+//
+// Маршрут: GET /api/v1/market/snapshot
+//
+// Параметры запроса (query):
+//   - currency: валюта котировки (string, необязательно; по умолчанию — все валюты)
+//
+// Возможные ответы:
+//   - 200 OK: последняя цена, рыночные показатели и изменение за 1h, 24h и 7d для каждой отслеживаемой пары.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MarketHandler) GetSnapshot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	req := new(models.MarketRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if req.Currency != common.Empty {
+		currency, err := common.NormalizeCurrency(req.Currency)
+		if err != nil {
+			common.ResponseBadRequest(c, "Unsupported currency")
+			return
+		}
+		req.Currency = currency
+	}
+
+	data, err := h.marketService.Snapshot(ctx, req.Currency)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+}
+
+// GetMovers обрабатывает запрос лидеров роста и падения цены.
+//
+// Маршрут: GET /api/v1/market/movers
+//
+// Параметры запроса (query):
+//   - window: окно изменения цены (string, по умолчанию 24h; например, 1h, 4h, 7d или число секунд, до 30 дней)
+//   - currency: валюта котировки (string, по умолчанию USD)
+//   - limit: размер каждого из списков (int, по умолчанию 10, максимум 100)
+//
+// Возможные ответы:
+//   - 200 OK: лидеры роста по убыванию изменения и лидеры падения по возрастанию изменения.
+//   - 400 Bad Request: некорректные входные данные.
+//   - 500 Internal Server Error: ошибка сервиса при получении данных.
+func (h *MarketHandler) GetMovers(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	req := new(models.MarketRequest)
+
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Error("ShouldBindQuery error", zap.Error(err))
+		common.ResponseBadRequest(c, "Invalid request")
+		return
+	}
+
+	if req.Currency == common.Empty {
+		req.Currency = common.DefaultCurrency
+	}
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return
+	}
+	if req.Window == common.Empty {
+		req.Window = common.DefaultMoversWindow
+	}
+	seconds, err := common.ParseInterval(req.Window)
+	if err != nil || seconds > common.MaxMarketWindow {
+		common.ResponseBadRequest(c, "Invalid window")
+		return
+	}
+	if req.Limit == common.Zero {
+		req.Limit = common.DefaultMoversLimit
+	}
+	if req.Limit < common.Zero || req.Limit > common.MaxMoversLimit {
+		common.ResponseBadRequest(c, "Invalid limit")
+		return
+	}
+
+	window := &models.MarketWindow{
+		Name:    strings.ToLower(strings.TrimSpace(req.Window)),
+		Seconds: seconds,
+	}
+	data, err := h.marketService.Movers(ctx, currency, window, req.Limit)
+	if err != nil {
+		zap.L().Error("DB error", zap.Error(err))
+		common.ResponseServerError(c, "Error while receiving data")
+		return
+	}
+	common.ResponseSuccess(c, common.Empty, data)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProviderHandler обрабатывает запросы о состоянии источников цен.
+type ProviderHandler struct {
+	registryClient interfaces.RegistryClientI
+}
+
+func NewProviderHandler(registryClient interfaces.RegistryClientI) *ProviderHandler {
+	return &ProviderHandler{
+		registryClient: registryClient,
+	}
+}
+
+// GetProviders обрабатывает запрос на получение состояния лимитов источников цен.
+//
+// Маршрут: GET /api/v1/providers
+//
+// Возможные ответы:
+//   - 200 OK: список источников с токенами, счётчиками запросов, повторов и ответов 429.
+func (h *ProviderHandler) GetProviders(c *gin.Context) {
+	common.ResponseSuccess(c, common.Empty, h.registryClient.Budgets())
+}
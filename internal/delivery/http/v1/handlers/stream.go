@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Типы сообщений и действия WebSocket-потока.
+const (
+	streamPrice      = "price"
+	streamSubscribed = "subscribed"
+	streamPong       = "pong"
+	streamError      = "error"
+
+	actionSubscribe   = "subscribe"
+	actionUnsubscribe = "unsubscribe"
+	actionPing        = "ping"
+)
+
+// StreamHandler обрабатывает подключения к потоку новых цен (SSE и WebSocket).
+type StreamHandler struct {
+	hub          interfaces.StreamHubI
+	coinRegistry interfaces.CoinRegistryI
+	upgrader     websocket.Upgrader
+}
+
+// NewStreamHandler создаёт обработчик потока цен.
+// WebSocket-подключения принимаются только с origin из allowOrigins ("*" — с любого).
+func NewStreamHandler(hub interfaces.StreamHubI, coinRegistry interfaces.CoinRegistryI, allowOrigins []string) *StreamHandler {
+	return &StreamHandler{
+		hub:          hub,
+		coinRegistry: coinRegistry,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == common.Empty || slices.Contains(allowOrigins, "*") || slices.Contains(allowOrigins, origin)
+			},
+		},
+	}
+}
+
+// StreamSSE обрабатывает подключение к потоку цен через Server-Sent Events.
+//
+// Маршрут: GET /api/v1/stream/sse
+//
+// Параметры запроса (query):
+//   - coins: монеты (ID, тикеры, названия или псевдонимы) через запятую (string, по умолчанию все монеты)
+//   - currencies: валюты через запятую (string, по умолчанию все валюты)
+//
+// События: price (новая цена, JSON), close (подписка закрыта сервером, причина в data).
+// Каждые 15 секунд отправляется комментарий heartbeat.
+//
+// Возможные ответы:
+//   - 200 OK: поток событий text/event-stream.
+//   - 400 Bad Request: неподдерживаемая валюта.
+//   - 409 Conflict: тикер или название монеты соответствует нескольким монетам (варианты в data).
+//   - 503 Service Unavailable: достигнут лимит подключений.
+func (h *StreamHandler) StreamSSE(c *gin.Context) {
+	filter, ok := h.streamFilter(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.hub.Subscribe(filter)
+	if err != nil {
+		zap.L().Warn("Stream subscribe error", zap.Error(err))
+		common.ResponseServiceUnavailable(c, "Too many stream connections")
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	// Поток живёт дольше WriteTimeout сервера, поэтому срок записи задаётся для каждого сообщения.
+	controller := http.NewResponseController(c.Writer)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	write := func(frame string) bool {
+		if err := controller.SetWriteDeadline(time.Now().Add(common.StreamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return false
+		}
+		if _, err := c.Writer.WriteString(frame); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	zap.L().Info("SSE stream connected", zap.Strings("coins:", filter.Coins), zap.Strings("currencies:", filter.Currencies))
+
+	heartbeat := time.NewTicker(common.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case events, ok := <-sub.Events():
+			if !ok {
+				reason := "closed"
+				if sub.Err() != nil {
+					reason = sub.Err().Error()
+				}
+				write(fmt.Sprintf("event: close\ndata: %s\n\n", reason))
+				return
+			}
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					zap.L().Error("Marshal price event error", zap.Error(err))
+					continue
+				}
+				if !write(fmt.Sprintf("event: %s\nid: %d\ndata: %s\n\n", streamPrice, event.Timestamp, data)) {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// StreamWS обрабатывает подключение к потоку цен через WebSocket.
+//
+// Маршрут: GET /api/v1/stream/ws
+//
+// Параметры запроса (query) задают начальную подписку так же, как для SSE.
+// Сообщения клиента (JSON): {"action": "subscribe" | "unsubscribe", "coins": [...], "currencies": [...]}
+// и {"action": "ping"}. Сообщения сервера (JSON): {"type": "price" | "subscribed" | "pong" | "error", ...}.
+// Сервер отправляет ping каждые 15 секунд и закрывает соединение, если pong не получен за 60 секунд.
+//
+// Возможные ответы:
+//   - 101 Switching Protocols: соединение установлено.
+//   - 400 Bad Request: неподдерживаемая валюта или некорректный запрос на upgrade.
+//   - 409 Conflict: тикер или название монеты соответствует нескольким монетам (варианты в data).
+//   - 503 Service Unavailable: достигнут лимит подключений.
+func (h *StreamHandler) StreamWS(c *gin.Context) {
+	filter, ok := h.streamFilter(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.hub.Subscribe(filter)
+	if err != nil {
+		zap.L().Warn("Stream subscribe error", zap.Error(err))
+		common.ResponseServiceUnavailable(c, "Too many stream connections")
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		zap.L().Error("WebSocket upgrade error", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	zap.L().Info("WebSocket stream connected", zap.Strings("coins:", filter.Coins), zap.Strings("currencies:", filter.Currencies))
+
+	// Писать в соединение может только одна горутина, поэтому ответы на команды
+	// передаются из горутины чтения в цикл записи через канал.
+	replies := make(chan *models.StreamMessage, common.StreamBufferSize)
+	done := make(chan struct{})
+	go h.readCommands(conn, sub, replies, done)
+
+	write := func(message any) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(common.StreamWriteTimeout))
+		return conn.WriteJSON(message) == nil
+	}
+
+	if !write(&models.StreamMessage{Type: streamSubscribed, Filter: sub.Filter()}) {
+		return
+	}
+
+	heartbeat := time.NewTicker(common.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case reply := <-replies:
+			if !write(reply) {
+				return
+			}
+		case events, ok := <-sub.Events():
+			if !ok {
+				reason := "closed"
+				if sub.Err() != nil {
+					reason = sub.Err().Error()
+				}
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, reason), time.Now().Add(common.StreamWriteTimeout))
+				return
+			}
+			for _, event := range events {
+				if !write(&models.StreamMessage{Type: streamPrice, Price: event}) {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(common.StreamWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommands читает команды клиента WebSocket, изменяет подписку и передаёт ответы в replies.
+// При ошибке чтения или истечении ожидания pong закрывает done.
+func (h *StreamHandler) readCommands(conn *websocket.Conn, sub interfaces.SubscriptionI, replies chan<- *models.StreamMessage, done chan<- struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(common.StreamReadLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(common.StreamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(common.StreamPongWait))
+	})
+
+	for {
+		command := new(models.StreamCommand)
+		if err := conn.ReadJSON(command); err != nil {
+			var (
+				syntaxErr *json.SyntaxError
+				typeErr   *json.UnmarshalTypeError
+			)
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				reply(replies, &models.StreamMessage{Type: streamError, Message: "Invalid command"})
+				continue
+			}
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(common.StreamPongWait))
+
+		switch command.Action {
+		case actionPing:
+			reply(replies, &models.StreamMessage{Type: streamPong})
+		case actionSubscribe, actionUnsubscribe:
+			currencies, err := normalizeStreamCurrencies(command.Currencies)
+			if err != nil {
+				reply(replies, &models.StreamMessage{Type: streamError, Message: "Unsupported currency"})
+				continue
+			}
+			coins, err := h.streamCoins(command.Coins)
+			if err != nil {
+				reply(replies, &models.StreamMessage{Type: streamError, Message: err.Error()})
+				continue
+			}
+			if command.Action == actionSubscribe {
+				sub.Subscribe(coins, currencies)
+			} else {
+				sub.Unsubscribe(coins, currencies)
+			}
+			reply(replies, &models.StreamMessage{Type: streamSubscribed, Filter: sub.Filter()})
+		default:
+			reply(replies, &models.StreamMessage{Type: streamError, Message: "Unknown action"})
+		}
+	}
+}
+
+// reply передаёт ответ циклу записи. Если клиент не читает ответы и буфер заполнен, ответ отбрасывается.
+func reply(replies chan<- *models.StreamMessage, message *models.StreamMessage) {
+	select {
+	case replies <- message:
+	default:
+	}
+}
+
+// streamFilter разбирает начальную подписку из query-параметров coins и currencies.
+// При неподдерживаемой валюте отвечает 400, при неоднозначном обозначении монеты — 409.
+func (h *StreamHandler) streamFilter(c *gin.Context) (*models.StreamFilter, bool) {
+	coins, err := h.streamCoins(splitList(c.Query("coins")))
+	if err != nil {
+		respondCoinError(c, err)
+		return nil, false
+	}
+	filter := &models.StreamFilter{
+		Coins: coins,
+	}
+
+	currencies, err := normalizeStreamCurrencies(splitList(c.Query("currencies")))
+	if err != nil {
+		zap.L().Error("Stream unsupported currency", zap.Error(err))
+		common.ResponseBadRequest(c, "Unsupported currency")
+		return nil, false
+	}
+	filter.Currencies = currencies
+	return filter, true
+}
+
+// streamCoins переводит обозначения монет подписки в ID реестра; монета "*" (все монеты) остаётся без изменений.
+func (h *StreamHandler) streamCoins(coins []string) ([]string, error) {
+	result := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		if coin == "*" {
+			result = append(result, coin)
+			continue
+		}
+		id, err := lookupCoin(h.coinRegistry, coin)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// normalizeStreamCurrencies приводит валюты к верхнему регистру; пустой список остаётся пустым (все валюты).
+func normalizeStreamCurrencies(currencies []string) ([]string, error) {
+	result := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		normalized, err := common.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы.
+func splitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != common.Empty {
+			result = append(result, item)
+		}
+	}
+	return result
+}
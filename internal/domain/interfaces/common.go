@@ -1,11 +1,18 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"testYTask/internal/domain/models"
+)
 
 type PingerI interface {
 	Ping(ctx context.Context) error
 }
 
+type HealthReporterI interface {
+	HealthReport() *models.HealthComponent
+}
+
 type CloserI interface {
 	Close() error
 }
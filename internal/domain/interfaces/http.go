@@ -10,7 +10,33 @@ type NexusI interface {
 	Stop() error
 }
 
+type PriceProviderI interface {
+	Name() string
+	BatchSize() int
+	Budget() *models.ProviderBudget
+	CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error)
+	CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string]models.CoinPrice, error)
+}
+
+// HistoryProviderI реализуется источниками, которые умеют отдавать историю цен за период.
+type HistoryProviderI interface {
+	Name() string
+	PriceRange(ctx context.Context, coin, currency string, from, to int64) ([]*models.PricePoint, error)
+	// HistoryStep возвращает наибольший интервал между точками истории за период не длиннее BackfillChunkWindow (сек).
+	HistoryStep() int64
+}
+
 type RegistryClientI interface {
-	LoadValidCoins(ctx context.Context) (map[string]bool, error)
-	CurrentData(ctx context.Context, coin string) (*models.CoinPrice, error)
+	LoadValidCoins(ctx context.Context) ([]*models.CoinInfo, error)
+	CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error)
+	CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string][]*models.ProviderQuote, error)
+	Providers() []PriceProviderI
+	Budgets() []*models.ProviderBudget
+	HistoryProvider() (HistoryProviderI, error)
+}
+
+// WebhookClientI отправляет оповещения на вебхуки.
+type WebhookClientI interface {
+	Validate(ctx context.Context, rawURL string) (string, error)
+	Send(ctx context.Context, delivery *models.AlertDelivery) (int, error)
 }
@@ -3,15 +3,61 @@ package interfaces
 import (
 	"context"
 	"testYTask/internal/domain/models"
+	"time"
 )
 
 type MajorRepositoryI interface {
 	AddingNewCoin(ctx context.Context, coin *models.Coin) error
 	DeleteCoin(ctx context.Context, coin *models.Coin) error
+	RemoveCurrencies(ctx context.Context, coin *models.Coin) error
 	GetPrice(ctx context.Context, coin *models.PriceRequest) (*models.PriceResponse, error)
+	GetPrices(ctx context.Context, reqs []*models.PriceRequest) ([]*models.PriceResponse, []error, error)
+	GetHistory(ctx context.Context, req *models.HistoryRequest) (*models.HistoryResponse, error)
+	GetCandles(ctx context.Context, req *models.CandleRequest) (*models.CandleResponse, error)
+	WatchedCoins(ctx context.Context) ([]*models.WatchedCoin, error)
+	ListWatchedCoins(ctx context.Context, req *models.WatchedCoinRequest) (*models.WatchedCoinList, error)
+	MarketTickers(ctx context.Context, currency string, windows []*models.MarketWindow) ([]*models.MarketTicker, error)
+	GetAveragePrice(ctx context.Context, req *models.AveragePriceRequest) (*models.AveragePriceResponse, error)
+	GetCloses(ctx context.Context, coins []string, currency string, from, to, step int64) (map[string][]*models.PricePoint, error)
 }
 
 type JobRepositoryI interface {
-	ListOfCurrentCoins(ctx context.Context) ([]string, error)
-	CoinDataUpdate(ctx context.Context, coin string, data *models.CoinPrice, precision int, currency string) error
+	ListOfCurrentCoins(ctx context.Context) ([]*models.WatchedCoin, error)
+	CoinDataUpdate(ctx context.Context, updates []*models.CoinUpdate) ([]*models.CoinUpdate, error)
+}
+
+type BackfillRepositoryI interface {
+	CreateBackfill(ctx context.Context, job *models.BackfillJob) (*models.BackfillJob, error)
+	FindActiveBackfill(ctx context.Context, coin, currency string, from, to int64) (*models.BackfillJob, error)
+	GetBackfill(ctx context.Context, id int64) (*models.BackfillJob, error)
+	ListBackfills(ctx context.Context, limit int) ([]*models.BackfillJob, error)
+	ClaimBackfill(ctx context.Context) (*models.BackfillJob, error)
+	ClaimBackfillByID(ctx context.Context, id int64) (*models.BackfillJob, error)
+	SaveBackfillChunk(ctx context.Context, job *models.BackfillJob, points []*models.PricePoint, cursor int64) (int64, error)
+	FinishBackfill(ctx context.Context, id int64, status, message string) error
+}
+
+type GapRepositoryI interface {
+	DetectGaps(ctx context.Context, since, threshold int64) ([]*models.PriceGap, error)
+	ListGaps(ctx context.Context, req *models.GapRequest) ([]*models.PriceGap, error)
+	GetGap(ctx context.Context, id int64) (*models.PriceGap, error)
+	AttachBackfill(ctx context.Context, id, backfillID int64) (*models.PriceGap, error)
+	SyncGapStatuses(ctx context.Context, maxStep int64) (int64, error)
+}
+
+type AlertRepositoryI interface {
+	CreateAlert(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error)
+	GetAlert(ctx context.Context, id int64) (*models.AlertRule, error)
+	ListAlerts(ctx context.Context) ([]*models.AlertRule, error)
+	ActiveAlerts(ctx context.Context, coins []string) ([]*models.AlertRule, error)
+	DeleteAlert(ctx context.Context, id int64) error
+	SaveEvaluation(ctx context.Context, evaluation *models.AlertEvaluation) error
+	ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*models.AlertDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int) ([]*models.AlertDelivery, error)
+	RecordDelivery(ctx context.Context, id int64, status string, code int, message string, retryIn time.Duration) error
+}
+
+type RegistryRepositoryI interface {
+	SaveRegistry(ctx context.Context, coins []*models.CoinInfo) error
+	LoadRegistry(ctx context.Context) ([]*models.CoinInfo, time.Time, error)
 }
@@ -0,0 +1,72 @@
+package interfaces
+
+import (
+	"context"
+	"testYTask/internal/domain/models"
+)
+
+type ConverterI interface {
+	Convert(ctx context.Context, req *models.ConvertRequest) (*models.ConvertResponse, error)
+}
+
+type BackfillerI interface {
+	Enqueue(ctx context.Context, req *models.BackfillRequest) (*models.BackfillJob, error)
+	Get(ctx context.Context, id int64) (*models.BackfillJob, error)
+	List(ctx context.Context, limit int) ([]*models.BackfillJob, error)
+	Retry(ctx context.Context, id int64) (*models.BackfillJob, error)
+	HistoryStep() int64
+}
+
+type GapDetectorI interface {
+	List(ctx context.Context, req *models.GapRequest) ([]*models.PriceGap, error)
+	Repair(ctx context.Context, id int64) (*models.PriceGap, error)
+}
+
+type MarketServiceI interface {
+	Snapshot(ctx context.Context, currency string) (*models.MarketSnapshot, error)
+	Movers(ctx context.Context, currency string, window *models.MarketWindow, limit int) (*models.MarketMovers, error)
+}
+
+type IndicatorServiceI interface {
+	Compute(ctx context.Context, req *models.IndicatorRequest) (*models.IndicatorResponse, error)
+}
+
+type AnalyticsServiceI interface {
+	Correlation(ctx context.Context, req *models.CorrelationRequest) (*models.CorrelationResponse, error)
+}
+
+// PriceListenerI получает новые цены после их сохранения.
+type PriceListenerI interface {
+	Publish(events []*models.PriceEvent)
+}
+
+// SubscriptionI — подписка одного соединения на поток цен.
+// Канал Events закрывается при отписке или отключении медленного потребителя; причина доступна через Err.
+type SubscriptionI interface {
+	Events() <-chan []*models.PriceEvent
+	Err() error
+	Filter() *models.StreamFilter
+	Subscribe(coins, currencies []string)
+	Unsubscribe(coins, currencies []string)
+}
+
+type StreamHubI interface {
+	Subscribe(filter *models.StreamFilter) (SubscriptionI, error)
+	Unsubscribe(sub SubscriptionI)
+	Stats() *models.StreamStats
+}
+
+type AlertServiceI interface {
+	Create(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error)
+	Get(ctx context.Context, id int64) (*models.AlertRule, error)
+	List(ctx context.Context) ([]*models.AlertRule, error)
+	Delete(ctx context.Context, id int64) error
+	Deliveries(ctx context.Context, id int64, limit int) ([]*models.AlertDelivery, error)
+}
+
+// CoinRegistryI — реестр валидных монет, безопасный для одновременного использования.
+type CoinRegistryI interface {
+	Resolve(coin string) (*models.CoinInfo, error)
+	Search(query string, limit int) []*models.CoinMatch
+	Stats() *models.RegistryStats
+}
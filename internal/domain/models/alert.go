@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AlertRequest описывает запрос на создание правила оповещения.
+//
+// Condition: above (цена выше Threshold), below (цена ниже Threshold) или change
+// (изменение цены за Window секунд по модулю не меньше Threshold процентов).
+// Если Secret не задан, он генерируется сервером и возвращается только в ответе на создание.
+type AlertRequest struct {
+	Coin       string `json:"coin" binding:"required"`
+	Currency   string `json:"currency"`
+	Condition  string `json:"condition" binding:"required"`
+	Threshold  string `json:"threshold" binding:"required"`
+	Window     int64  `json:"window"`
+	WebhookURL string `json:"webhook_url" binding:"required"`
+	Secret     string `json:"secret"`
+}
+
+// AlertRule описывает правило оповещения.
+//
+// Правило срабатывает при переходе условия из ложного в истинное (Triggered = true)
+// и снова становится активным, когда условие перестаёт выполняться.
+type AlertRule struct {
+	ID              int64     `json:"id"`
+	Coin            string    `json:"coin"`
+	Currency        string    `json:"currency"`
+	Condition       string    `json:"condition"`
+	Threshold       Decimal   `json:"threshold"`
+	Window          int64     `json:"window,omitempty"`
+	WebhookURL      string    `json:"webhook_url"`
+	Secret          string    `json:"secret,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	Triggered       bool      `json:"triggered"`
+	LastTriggeredAt *int64    `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AlertEvent — тело запроса на вебхук при срабатывании правила.
+// Для условия change заполняются BasePrice, BaseTimestamp и ChangePercent.
+type AlertEvent struct {
+	AlertID       int64   `json:"alert_id"`
+	Coin          string  `json:"coin"`
+	Currency      string  `json:"currency"`
+	Condition     string  `json:"condition"`
+	Threshold     Number  `json:"threshold"`
+	Window        int64   `json:"window,omitempty"`
+	Price         Number  `json:"price"`
+	Timestamp     int64   `json:"timestamp"`
+	BasePrice     *Number `json:"base_price,omitempty"`
+	BaseTimestamp *int64  `json:"base_timestamp,omitempty"`
+	ChangePercent *Number `json:"change_percent,omitempty"`
+}
+
+// AlertDelivery — запись журнала доставки оповещения на вебхук.
+type AlertDelivery struct {
+	ID             int64           `json:"id"`
+	RuleID         int64           `json:"rule_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	WebhookURL     string          `json:"-"`
+	Secret         string          `json:"-"`
+}
+
+// AlertEvaluation — результат проверки правил после сохранения цен:
+// сработавшие правила с оповещениями и правила, условие которых перестало выполняться.
+type AlertEvaluation struct {
+	Fired   []*AlertEvent
+	Rearmed []int64
+}
@@ -0,0 +1,33 @@
+package models
+
+// CorrelationRequest описывает запрос матриц корреляции и ковариации доходностей монет.
+// Coins — список монет через запятую; Returns — log (логарифмические доходности) или simple (относительные).
+// CoinList и Step заполняются обработчиком.
+type CorrelationRequest struct {
+	Coins    string   `form:"coins"`
+	From     int64    `form:"from"`
+	To       int64    `form:"to"`
+	Interval string   `form:"interval"`
+	Currency string   `form:"currency"`
+	Returns  string   `form:"returns"`
+	CoinList []string `form:"-"`
+	Step     int64    `form:"-"`
+}
+
+// CorrelationResponse содержит матрицы по доходностям монет, выровненным по общим интервалам.
+//
+// Строки и столбцы матриц соответствуют Coins. Observations — число интервалов, в которых есть доходность
+// каждой монеты. Ковариация — выборочная (делитель n − 1). Корреляция равна null, если доходность монеты
+// за окно не менялась.
+type CorrelationResponse struct {
+	Coins        []string     `json:"coins"`
+	Currency     string       `json:"currency"`
+	Interval     int64        `json:"interval"`
+	From         int64        `json:"from"`
+	To           int64        `json:"to"`
+	Returns      string       `json:"returns"`
+	Observations int          `json:"observations"`
+	Pearson      [][]*float64 `json:"pearson"`
+	Spearman     [][]*float64 `json:"spearman"`
+	Covariance   [][]*float64 `json:"covariance"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// AveragePriceRequest описывает запрос средних цен монеты за окно [From, To].
+// MaxGap — наибольшее время (сек), в течение которого сэмпл считается действующим без следующего сэмпла.
+type AveragePriceRequest struct {
+	Coin     string `form:"-"`
+	From     int64  `form:"from"`
+	To       int64  `form:"to"`
+	Currency string `form:"currency"`
+	MaxGap   int64  `form:"max_gap"`
+}
+
+// AveragePriceResponse содержит средневзвешенные по времени (TWAP) и по объёму (VWAP) цены за окно.
+//
+// Samples — число сэмплов с ненулевым весом, CoveredSeconds и Coverage — время окна, покрытое действующими
+// сэмплами, в секундах и в доле от длины окна. Поля с префиксом Volume относятся к VWAP и учитывают только
+// сэмплы с сохранённым объёмом; Volume — оценка объёма торгов за окно в валюте котировки.
+// TWAP и VWAP равны null, если в окне нет подходящих сэмплов.
+type AveragePriceResponse struct {
+	Coin           string   `json:"coin"`
+	Currency       string   `json:"currency"`
+	From           int64    `json:"from"`
+	To             int64    `json:"to"`
+	MaxGap         int64    `json:"max_gap"`
+	TWAP           *Decimal `json:"twap"`
+	Samples        int64    `json:"samples"`
+	CoveredSeconds int64    `json:"covered_seconds"`
+	Coverage       Decimal  `json:"coverage"`
+	VWAP           *Decimal `json:"vwap"`
+	Volume         *Decimal `json:"volume,omitempty"`
+	VolumeSamples  int64    `json:"volume_samples"`
+	VolumeCoverage Decimal  `json:"volume_coverage"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BackfillRequest описывает запрос на дозагрузку истории цен монеты за период.
+type BackfillRequest struct {
+	Coin     string `json:"coin" binding:"required"`
+	Currency string `json:"currency"`
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+}
+
+// BackfillJob описывает задачу дозагрузки истории и её прогресс.
+//
+// Cursor — метка времени, до которой история уже загружена: после перезапуска
+// задача продолжает с Cursor, а не с From. Fetched — число точек, полученных от источника,
+// Inserted — число новых строк в currency_prices (остальные уже были сохранены).
+type BackfillJob struct {
+	ID        int64     `json:"id"`
+	Coin      string    `json:"coin"`
+	Currency  string    `json:"currency"`
+	Provider  string    `json:"provider"`
+	From      int64     `json:"from"`
+	To        int64     `json:"to"`
+	Cursor    int64     `json:"cursor"`
+	Status    string    `json:"status"`
+	Fetched   int64     `json:"fetched"`
+	Inserted  int64     `json:"inserted"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PricePoint — историческая точка цены, полученная от источника.
+type PricePoint struct {
+	Timestamp int64
+	Price     decimal.Decimal
+}
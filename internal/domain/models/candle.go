@@ -0,0 +1,31 @@
+package models
+
+type CandleRequest struct {
+	Coin     string `form:"-"`
+	From     int64  `form:"from"`
+	To       int64  `form:"to"`
+	Interval string `form:"interval"`
+	Currency string `form:"currency"`
+	Step     int64  `form:"-"`
+}
+
+// Candle описывает OHLC-свечу за один интервал.
+// Для интервалов без сэмплов Empty = true, а цены равны null.
+type Candle struct {
+	Timestamp int64    `json:"timestamp"`
+	Open      *Decimal `json:"open"`
+	High      *Decimal `json:"high"`
+	Low       *Decimal `json:"low"`
+	Close     *Decimal `json:"close"`
+	Count     int64    `json:"count"`
+	Empty     bool     `json:"empty"`
+}
+
+type CandleResponse struct {
+	Coin     string    `json:"coin"`
+	Currency string    `json:"currency"`
+	Interval int64     `json:"interval"`
+	From     int64     `json:"from"`
+	To       int64     `json:"to"`
+	Candles  []*Candle `json:"candles"`
+}
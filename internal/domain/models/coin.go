@@ -1,9 +1,55 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type Coin struct {
-	NameCoin string `json:"name_coin" binding:"required"`
+	NameCoin   string   `json:"name_coin" binding:"required"`
+	Currencies []string `json:"currencies"`
+}
+
+// WatchedCoin описывает отслеживаемую монету и валюты, в которых сохраняется её цена.
+type WatchedCoin struct {
+	Symbol     string
+	Currencies []string
+}
+
+// CoinPrice содержит котировки монеты по валютам.
+// Ключ — код валюты в верхнем регистре (например, "USD").
+type CoinPrice map[string]*MarketQuote
+
+// MarketData содержит рыночные показатели монеты в валюте котировки: капитализацию, объём торгов
+// за 24 часа и изменение цены за 24 часа (%). Показатели, которые источник не возвращает, равны nil.
+type MarketData struct {
+	MarketCap *Decimal `json:"market_cap,omitempty"`
+	Volume24h *Decimal `json:"volume_24h,omitempty"`
+	Change24h *Decimal `json:"change_24h,omitempty"`
+}
+
+// MarketQuote описывает цену монеты в одной валюте вместе с рыночными показателями.
+// UpdatedAt — время последнего обновления данных у источника (unix, сек), 0 — источник его не сообщает.
+type MarketQuote struct {
+	Price decimal.Decimal
+	MarketData
+	UpdatedAt int64
+}
+
+// ProviderQuote описывает котировку монеты, полученную от одного источника.
+type ProviderQuote struct {
+	Provider string          `json:"provider"`
+	Currency string          `json:"currency"`
+	Price    decimal.Decimal `json:"price"`
+	MarketData
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+	Outlier   bool  `json:"outlier"`
 }
 
-type CoinPrice struct {
-	USD float64 `json:"usd"`
+// CoinUpdate описывает согласованную цену монеты в одной валюте на момент Timestamp, рыночные показатели
+// и котировки, из которых она получена. Timestamp — время обновления данных у источника.
+type CoinUpdate struct {
+	Coin     string
+	Currency string
+	Price    decimal.Decimal
+	MarketData
+	Timestamp int64
+	Quotes    []*ProviderQuote
 }
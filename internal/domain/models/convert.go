@@ -0,0 +1,31 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// ConvertRequest описывает запрос на пересчёт суммы.
+// Amount принимается строкой, чтобы не терять точность; разобранное значение хранится в Value.
+type ConvertRequest struct {
+	From      string          `form:"from"`
+	To        string          `form:"to"`
+	Amount    string          `form:"amount"`
+	Timestamp int64           `form:"timestamp"`
+	Value     decimal.Decimal `form:"-"`
+}
+
+// ConvertResponse описывает результат пересчёта суммы по сохранённым ценам.
+//
+// Route показывает, как получен курс: direct — прямая цена монеты в валюте, inverse — обратная цена,
+// triangulated — произведение курсов нескольких звеньев через промежуточные монеты и валюты из Via.
+// Skew — разница между самым ранним и самым поздним из использованных сэмплов (сек).
+type ConvertResponse struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Amount    Decimal          `json:"amount"`
+	Rate      Decimal          `json:"rate"`
+	Result    Decimal          `json:"result"`
+	Timestamp int64            `json:"timestamp"`
+	Route     string           `json:"route"`
+	Via       []string         `json:"via,omitempty"`
+	Samples   []*PriceResponse `json:"samples"`
+	Skew      int64            `json:"skew"`
+}
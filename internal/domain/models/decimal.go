@@ -0,0 +1,59 @@
+package models
+
+import (
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+)
+
+// decimalsAsStrings — формат Decimal в JSON, задаётся один раз при запуске (APP_PRICE_AS_STRING).
+var decimalsAsStrings atomic.Bool
+
+// SetDecimalsAsStrings задаёт формат Decimal в ответах API: строкой ("0.000000001") или числом.
+func SetDecimalsAsStrings(value bool) {
+	decimalsAsStrings.Store(value)
+}
+
+// Decimal — точное десятичное значение в ответах API. Выводится в JSON числом или строкой по SetDecimalsAsStrings;
+// глобальная настройка пакета decimal не используется, поэтому формат не влияет на другие данные в JSON.
+type Decimal struct {
+	decimal.Decimal
+}
+
+// NewDecimal оборачивает значение для ответа API.
+func NewDecimal(value decimal.Decimal) Decimal {
+	return Decimal{Decimal: value}
+}
+
+// NewDecimalPtr оборачивает необязательное значение для ответа API; nil остаётся nil.
+func NewDecimalPtr(value *decimal.Decimal) *Decimal {
+	if value == nil {
+		return nil
+	}
+	return &Decimal{Decimal: *value}
+}
+
+// Unwrap возвращает значение необязательного Decimal; nil остаётся nil.
+func (d *Decimal) Unwrap() *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+	return &d.Decimal
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if decimalsAsStrings.Load() {
+		return []byte(`"` + d.String() + `"`), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// Number — точное десятичное значение, которое всегда выводится в JSON числом. Используется в телах вебхуков,
+// формат которых не зависит от настройки ответов API.
+type Number struct {
+	decimal.Decimal
+}
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	return []byte(n.String()), nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDecimalJSON(t *testing.T) {
+	price := decimal.RequireFromString("0.000000001234")
+	value := struct {
+		Price  Decimal  `json:"price"`
+		Change *Decimal `json:"change,omitempty"`
+		Base   Number   `json:"base"`
+	}{Price: NewDecimal(price), Change: NewDecimalPtr(nil), Base: Number{Decimal: price}}
+
+	tests := []struct {
+		name     string
+		asString bool
+		want     string
+	}{
+		{name: "numbers", want: `{"price":0.000000001234,"base":0.000000001234}`},
+		{name: "strings", asString: true, want: `{"price":"0.000000001234","base":0.000000001234}`},
+	}
+	defer SetDecimalsAsStrings(false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDecimalsAsStrings(tt.asString)
+			got, err := json.Marshal(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if decimal.MarshalJSONWithoutQuotes {
+				t.Error("package decimal setting changed")
+			}
+
+			var decoded struct {
+				Price Decimal `json:"price"`
+			}
+			if err := json.Unmarshal(got, &decoded); err != nil || !decoded.Price.Equal(price) {
+				t.Errorf("round trip: got %v, %v", decoded.Price, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// GapRequest описывает фильтры списка пропусков.
+type GapRequest struct {
+	Coin     string `form:"coin"`
+	Currency string `form:"currency"`
+	Status   string `form:"status"`
+	Limit    int    `form:"limit"`
+}
+
+// PriceGap описывает пропуск в истории цен: между Start и End нет ни одной цены.
+//
+// Start и End — метки времени соседних сохранённых цен, Duration = End - Start (сек). Для пропуска в конце
+// истории End — время последнего сканирования.
+// BackfillID — задача дозагрузки, созданная для заполнения пропуска.
+type PriceGap struct {
+	ID         int64     `json:"id"`
+	Coin       string    `json:"coin"`
+	Currency   string    `json:"currency"`
+	Start      int64     `json:"start"`
+	End        int64     `json:"end"`
+	Duration   int64     `json:"duration"`
+	Status     string    `json:"status"`
+	BackfillID *int64    `json:"backfill_id,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// HealthComponent описывает состояние одного компонента в подробном ответе /healthz.
+type HealthComponent struct {
+	Name    string      `json:"name"`
+	Healthy bool        `json:"healthy"`
+	Detail  interface{} `json:"detail,omitempty"`
+}
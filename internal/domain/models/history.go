@@ -0,0 +1,19 @@
+package models
+
+type HistoryRequest struct {
+	Coin     string `form:"-"`
+	From     int64  `form:"from"`
+	To       int64  `form:"to"`
+	Limit    int    `form:"limit"`
+	Cursor   int64  `form:"cursor"`
+	Currency string `form:"currency"`
+}
+
+type HistoryResponse struct {
+	Coin       string           `json:"coin"`
+	Currency   string           `json:"currency"`
+	From       int64            `json:"from"`
+	To         int64            `json:"to"`
+	Items      []*PriceResponse `json:"items"`
+	NextCursor *int64           `json:"next_cursor,omitempty"`
+}
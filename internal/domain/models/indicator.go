@@ -0,0 +1,35 @@
+package models
+
+// IndicatorRequest описывает запрос технических индикаторов монеты за период.
+// Indicators — список описаний через запятую (например, "sma:50,rsi,macd:12:26:9"), пустой — все индикаторы
+// с параметрами по умолчанию. Step — длительность интервала Interval в секундах.
+type IndicatorRequest struct {
+	Coin       string `form:"-"`
+	From       int64  `form:"from"`
+	To         int64  `form:"to"`
+	Interval   string `form:"interval"`
+	Currency   string `form:"currency"`
+	Indicators string `form:"indicators"`
+	Step       int64  `form:"-"`
+}
+
+// IndicatorPoint — цена закрытия интервала и значения индикаторов по ней; Timestamp — начало интервала, как у свечей.
+// Filled = true, если в интервале не было сэмплов и цена закрытия перенесена из предыдущего интервала.
+// Значения индикаторов равны null, пока индикатору не хватает истории.
+type IndicatorPoint struct {
+	Timestamp int64               `json:"timestamp"`
+	Close     Decimal             `json:"close"`
+	Filled    bool                `json:"filled,omitempty"`
+	Values    map[string]*float64 `json:"values"`
+}
+
+// IndicatorResponse описывает значения индикаторов по интервалам; Columns — имена значений в Values.
+type IndicatorResponse struct {
+	Coin     string            `json:"coin"`
+	Currency string            `json:"currency"`
+	Interval int64             `json:"interval"`
+	From     int64             `json:"from"`
+	To       int64             `json:"to"`
+	Columns  []string          `json:"columns"`
+	Points   []*IndicatorPoint `json:"points"`
+}
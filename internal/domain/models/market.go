@@ -0,0 +1,60 @@
+package models
+
+// MarketRequest описывает запрос снимка рынка или лидеров роста и падения.
+// Window и Limit используются только для лидеров.
+type MarketRequest struct {
+	Currency string `form:"currency"`
+	Window   string `form:"window"`
+	Limit    int    `form:"limit"`
+}
+
+// MarketWindow — окно расчёта изменения цены: Name — обозначение из запроса (например, "24h"), Seconds — длительность.
+type MarketWindow struct {
+	Name    string
+	Seconds int64
+}
+
+// PriceChange — изменение цены за окно в процентах относительно опорного сэмпла: последнего сэмпла
+// не позже (время последней цены − окно) и не раньше (время последней цены − 2 × окно).
+// Percent и опорный сэмпл не заполняются, если такого сэмпла нет.
+type PriceChange struct {
+	Percent            *Decimal `json:"percent"`
+	ReferencePrice     *Decimal `json:"reference_price,omitempty"`
+	ReferenceTimestamp *int64   `json:"reference_timestamp,omitempty"`
+}
+
+// MarketTicker — последняя цена отслеживаемой монеты в одной валюте и её изменения по окнам.
+type MarketTicker struct {
+	Coin      string  `json:"coin"`
+	Currency  string  `json:"currency"`
+	Price     Decimal `json:"price"`
+	Timestamp int64   `json:"timestamp"`
+	MarketData
+	Changes map[string]*PriceChange `json:"changes"`
+}
+
+// MarketSnapshot — последние цены всех отслеживаемых пар; Timestamp — время формирования снимка.
+type MarketSnapshot struct {
+	Currency  string          `json:"currency,omitempty"`
+	Timestamp int64           `json:"timestamp"`
+	Items     []*MarketTicker `json:"items"`
+}
+
+// MarketMover — монета из списка лидеров роста или падения; Change — изменение цены за окно (%).
+type MarketMover struct {
+	Coin               string  `json:"coin"`
+	Currency           string  `json:"currency"`
+	Price              Decimal `json:"price"`
+	Timestamp          int64   `json:"timestamp"`
+	ReferencePrice     Decimal `json:"reference_price"`
+	ReferenceTimestamp int64   `json:"reference_timestamp"`
+	Change             Decimal `json:"change"`
+}
+
+// MarketMovers — лидеры роста (по убыванию изменения) и падения (по возрастанию) за окно Window.
+type MarketMovers struct {
+	Currency string         `json:"currency"`
+	Window   string         `json:"window"`
+	Gainers  []*MarketMover `json:"gainers"`
+	Losers   []*MarketMover `json:"losers"`
+}
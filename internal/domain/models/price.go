@@ -1,21 +1,60 @@
 package models
 
+// PriceRequest описывает запрос цены монеты на момент Timestamp.
+//
+// Mode задаёт выбор сэмпла: nearest (ближайший в любую сторону), before (последний не позже Timestamp),
+// after (первый не раньше Timestamp) или linear (линейная интерполяция между соседними сэмплами).
+// MaxDistance ограничивает расстояние до использованных сэмплов (сек), 0 — без ограничения.
 type PriceRequest struct {
-	Coin      string `json:"coin" binding:"required"`
-	Timestamp int64  `json:"timestamp" binding:"required"`
+	Coin        string `json:"coin" binding:"required"`
+	Timestamp   int64  `json:"timestamp" binding:"required"`
+	Currency    string `json:"currency"`
+	Mode        string `json:"mode"`
+	MaxDistance int64  `json:"max_distance"`
 }
 
 type DbResponse struct {
-	Coin      string  `db:"coin"`
-	Price     float64 `db:"price"`
-	Precision int     `db:"precision"`
-	Currency  string  `db:"currency"`
-	Timestamp int64   `db:"timestamp"`
+	Coin      string `db:"coin"`
+	Price     int64  `db:"price"`
+	Precision int    `db:"precision"`
+	Currency  string `db:"currency"`
+	Timestamp int64  `db:"timestamp"`
 }
 
+// BatchPriceRequest описывает пакетный запрос цен: каждый элемент обрабатывается как отдельный PriceRequest.
+type BatchPriceRequest struct {
+	Items []*PriceRequest `json:"items" binding:"required"`
+}
+
+// BatchPriceItem — результат одного элемента пакетного запроса.
+// Index — позиция элемента в запросе; Status и Message имеют тот же смысл, что и в ответе одиночного запроса.
+type BatchPriceItem struct {
+	Index   int            `json:"index"`
+	Status  string         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Price   *PriceResponse `json:"price,omitempty"`
+}
+
+// BatchPriceResponse описывает результат пакетного запроса цен.
+type BatchPriceResponse struct {
+	Items  []*BatchPriceItem `json:"items"`
+	Found  int               `json:"found"`
+	Failed int               `json:"failed"`
+}
+
+// PriceResponse описывает цену монеты.
+//
+// Для ответа на PriceRequest заполняются Mode, SampleTimestamps — метки времени использованных сэмплов
+// и Distance — наибольшее расстояние от запрошенного момента до них (сек). Timestamp для режима linear
+// равен запрошенному моменту, для остальных режимов — метке времени сэмпла.
+// Рыночные показатели берутся из сэмпла (для linear — из ближайшего к запрошенному моменту), если они сохранены.
 type PriceResponse struct {
 	Coin      string  `json:"coin"`
-	Price     float64 `json:"price"`
+	Price     Decimal `json:"price"`
 	Currency  string  `json:"currency"`
 	Timestamp int64   `json:"timestamp"`
+	MarketData
+	Mode             string  `json:"mode,omitempty"`
+	SampleTimestamps []int64 `json:"sample_timestamps,omitempty"`
+	Distance         *int64  `json:"distance,omitempty"`
 }
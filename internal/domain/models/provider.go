@@ -0,0 +1,25 @@
+package models
+
+// ProviderBudget описывает состояние лимитов запросов к источнику цен.
+type ProviderBudget struct {
+	Provider          string        `json:"provider"`
+	RequestsPerMinute float64       `json:"requests_per_minute"`
+	Burst             int           `json:"burst"`
+	TokensAvailable   float64       `json:"tokens_available"`
+	Requests          int64         `json:"requests"`
+	Retries           int64         `json:"retries"`
+	RateLimited       int64         `json:"rate_limited"`
+	Failures          int64         `json:"failures"`
+	LastStatus        int           `json:"last_status,omitempty"`
+	BlockedUntil      int64         `json:"blocked_until,omitempty"`
+	Circuit           *CircuitState `json:"circuit,omitempty"`
+}
+
+// CircuitState описывает состояние автомата защиты источника.
+type CircuitState struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Opens               int64  `json:"opens"`
+	OpenedAt            int64  `json:"opened_at,omitempty"`
+	RetryAt             int64  `json:"retry_at,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// CoinInfo описывает монету из списка валидных монет источника.
+type CoinInfo struct {
+	ID            string `json:"id"`
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MarketCapRank *int   `json:"market_cap_rank,omitempty"`
+}
+
+// RegistryStats описывает состояние реестра валидных монет.
+//
+// Source — откуда загружен текущий список: provider (источник цен) или database (сохранённая копия);
+// пустое значение означает, что список ещё не загружен. LastError — ошибка последнего обновления.
+type RegistryStats struct {
+	Coins       int       `json:"coins"`
+	Source      string    `json:"source"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	LastAttempt time.Time `json:"last_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// CoinMatch — монета, найденная поиском по реестру.
+//
+// MatchedOn — поле, по которому найдена монета: id, alias, symbol или name.
+// Match — тип совпадения от лучшего к худшему: exact, prefix, substring или fuzzy (с опечатками).
+type CoinMatch struct {
+	*CoinInfo
+	MatchedOn string `json:"matched_on"`
+	Match     string `json:"match"`
+}
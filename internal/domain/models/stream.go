@@ -0,0 +1,43 @@
+package models
+
+// PriceEvent — новая цена монеты, отправляемая подписчикам потока после сохранения в currency_prices.
+type PriceEvent struct {
+	Coin      string  `json:"coin"`
+	Currency  string  `json:"currency"`
+	Price     Decimal `json:"price"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+// StreamFilter описывает подписку соединения: пустой список означает все монеты (все валюты).
+type StreamFilter struct {
+	Coins      []string `json:"coins"`
+	Currencies []string `json:"currencies"`
+}
+
+// StreamCommand — сообщение клиента WebSocket.
+//
+// Action: subscribe (добавить монеты и валюты), unsubscribe (убрать) или ping.
+// Монета "*" в subscribe подписывает на все монеты, в unsubscribe — отменяет все подписки.
+type StreamCommand struct {
+	Action     string   `json:"action"`
+	Coins      []string `json:"coins"`
+	Currencies []string `json:"currencies"`
+}
+
+// StreamMessage — сообщение сервера WebSocket.
+//
+// Type: price (новая цена в Price), subscribed (текущая подписка в Filter), pong или error (текст в Message).
+type StreamMessage struct {
+	Type    string        `json:"type"`
+	Price   *PriceEvent   `json:"price,omitempty"`
+	Filter  *StreamFilter `json:"filter,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// StreamStats — счётчики потока цен для метрик.
+type StreamStats struct {
+	Subscribers int   `json:"subscribers"`
+	Published   int64 `json:"published"`
+	Delivered   int64 `json:"delivered"`
+	Evicted     int64 `json:"evicted"`
+}
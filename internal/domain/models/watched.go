@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// WatchedCoinRequest описывает запрос списка отслеживаемых монет.
+//
+// Coin — подстрока ID монеты, Currency — валюта котировки; Stale оставляет только устаревшие (true)
+// или только актуальные (false) монеты. Монета считается устаревшей, если хотя бы одна её пара
+// не получала цену дольше StaleAfter секунд или не получала её ни разу.
+type WatchedCoinRequest struct {
+	Coin       string `form:"coin"`
+	Currency   string `form:"currency"`
+	Stale      *bool  `form:"stale"`
+	StaleAfter int64  `form:"stale_after"`
+	Sort       string `form:"sort"`
+	Order      string `form:"order"`
+	Limit      int    `form:"limit"`
+	Offset     int    `form:"offset"`
+}
+
+// WatchedQuote — состояние цены монеты в одной валюте котировки.
+// LastPrice, LastTimestamp и Staleness (сек с последней цены) не заполняются, если цен ещё нет.
+type WatchedQuote struct {
+	Currency      string   `json:"currency"`
+	LastPrice     *Decimal `json:"last_price,omitempty"`
+	LastTimestamp *int64   `json:"last_timestamp,omitempty"`
+	Samples       int64    `json:"samples"`
+	Staleness     *int64   `json:"staleness,omitempty"`
+	Stale         bool     `json:"stale"`
+}
+
+// WatchedCoinInfo — отслеживаемая монета и состояние её цен.
+// LastTimestamp — самая свежая цена среди валют, Samples — сумма по валютам,
+// Staleness — наибольшая устарелость среди валют.
+type WatchedCoinInfo struct {
+	Coin          string          `json:"coin"`
+	AddedAt       *time.Time      `json:"added_at,omitempty"`
+	LastTimestamp *int64          `json:"last_timestamp,omitempty"`
+	Samples       int64           `json:"samples"`
+	Staleness     *int64          `json:"staleness,omitempty"`
+	Stale         bool            `json:"stale"`
+	Quotes        []*WatchedQuote `json:"quotes"`
+}
+
+// WatchedCoinList — страница списка отслеживаемых монет; Total — число монет после фильтрации.
+type WatchedCoinList struct {
+	Items  []*WatchedCoinInfo `json:"items"`
+	Total  int                `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
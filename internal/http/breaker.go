@@ -0,0 +1,124 @@
+package http
+
+import (
+	"sync"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/models"
+	"time"
+)
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// circuitBreaker защищает источник от запросов, пока он недоступен.
+//
+// В состоянии closed запросы проходят, и после FailureThreshold неудач подряд автомат
+// переходит в open. В open запросы отклоняются с ErrCircuitOpen до истечения Cooldown,
+// после чего автомат переходит в half-open и пропускает один пробный запрос: успех
+// замыкает автомат, неудача снова размыкает его.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	opens    int64
+}
+
+// newCircuitBreaker создаёт замкнутый автомат. Нулевые значения настроек заменяются значениями по умолчанию.
+func newCircuitBreaker(cfg *config.BreakerConf) *circuitBreaker {
+	breaker := &circuitBreaker{
+		threshold: common.DefaultBreakerThreshold,
+		cooldown:  common.DefaultBreakerCooldown,
+		state:     BreakerClosed,
+	}
+	if cfg != nil && cfg.FailureThreshold > common.Zero {
+		breaker.threshold = cfg.FailureThreshold
+	}
+	if cfg != nil && cfg.CooldownSec > common.Zero {
+		breaker.cooldown = time.Duration(cfg.CooldownSec) * time.Second
+	}
+	return breaker
+}
+
+// Allow сообщает, можно ли выполнить запрос. Возвращает ErrCircuitOpen, если нельзя.
+func (b *circuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return common.ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return common.ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success фиксирует успешный запрос и замыкает автомат.
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure фиксирует неудачный запрос и при необходимости размыкает автомат.
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		if b.state != BreakerOpen {
+			b.opens++
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Cancel снимает признак пробного запроса, если запрос прерван без ответа источника.
+func (b *circuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State возвращает текущее состояние автомата.
+func (b *circuitBreaker) State() *models.CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := &models.CircuitState{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Opens:               b.opens,
+	}
+	if b.state != BreakerClosed {
+		state.OpenedAt = b.openedAt.Unix()
+		state.RetryAt = b.openedAt.Add(b.cooldown).Unix()
+	}
+	return state
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testing"
+	"time"
+)
+
+// expire переносит момент размыкания автомата в прошлое, чтобы Cooldown истёк.
+func expire(b *circuitBreaker) {
+	b.mu.Lock()
+	b.openedAt = time.Now().Add(-b.cooldown - time.Second)
+	b.mu.Unlock()
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		action string // allow, deny, success, failure, cancel, expire
+		state  string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		opens int64
+	}{
+		{
+			name: "opens after threshold failures in a row",
+			steps: []step{
+				{"allow", BreakerClosed}, {"failure", BreakerClosed},
+				{"allow", BreakerClosed}, {"failure", BreakerClosed},
+				{"allow", BreakerClosed}, {"failure", BreakerOpen},
+				{"deny", BreakerOpen},
+			},
+			opens: 1,
+		},
+		{
+			name: "success resets the failure count",
+			steps: []step{
+				{"failure", BreakerClosed}, {"failure", BreakerClosed}, {"success", BreakerClosed},
+				{"failure", BreakerClosed}, {"failure", BreakerClosed},
+			},
+		},
+		{
+			name: "half-open probe success closes",
+			steps: []step{
+				{"failure", BreakerClosed}, {"failure", BreakerClosed}, {"failure", BreakerOpen},
+				{"expire", BreakerOpen}, {"allow", BreakerHalfOpen}, {"deny", BreakerHalfOpen},
+				{"success", BreakerClosed}, {"allow", BreakerClosed},
+			},
+			opens: 1,
+		},
+		{
+			name: "half-open probe failure reopens",
+			steps: []step{
+				{"failure", BreakerClosed}, {"failure", BreakerClosed}, {"failure", BreakerOpen},
+				{"expire", BreakerOpen}, {"allow", BreakerHalfOpen},
+				{"failure", BreakerOpen}, {"deny", BreakerOpen},
+			},
+			opens: 2,
+		},
+		{
+			name: "cancelled probe lets the next request probe",
+			steps: []step{
+				{"failure", BreakerClosed}, {"failure", BreakerClosed}, {"failure", BreakerOpen},
+				{"expire", BreakerOpen}, {"allow", BreakerHalfOpen}, {"cancel", BreakerHalfOpen},
+				{"allow", BreakerHalfOpen}, {"deny", BreakerHalfOpen},
+			},
+			opens: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker(&config.BreakerConf{FailureThreshold: 3, CooldownSec: 60})
+			for i, s := range tt.steps {
+				switch s.action {
+				case "allow":
+					if err := b.Allow(); err != nil {
+						t.Fatalf("step %d: Allow() = %v", i, err)
+					}
+				case "deny":
+					if err := b.Allow(); !errors.Is(err, common.ErrCircuitOpen) {
+						t.Fatalf("step %d: Allow() = %v, want ErrCircuitOpen", i, err)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "cancel":
+					b.Cancel()
+				case "expire":
+					expire(b)
+				}
+				if state := b.State().State; state != s.state {
+					t.Fatalf("step %d (%s): state %s, want %s", i, s.action, state, s.state)
+				}
+			}
+			if opens := b.State().Opens; opens != tt.opens {
+				t.Errorf("opens = %d, want %d", opens, tt.opens)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerDefaults(t *testing.T) {
+	b := newCircuitBreaker(nil)
+	if b.threshold != common.DefaultBreakerThreshold || b.cooldown != common.DefaultBreakerCooldown {
+		t.Errorf("got threshold %d, cooldown %s", b.threshold, b.cooldown)
+	}
+
+	b.Failure()
+	state := b.State()
+	if state.OpenedAt != 0 || state.RetryAt != 0 || state.ConsecutiveFailures != 1 {
+		t.Errorf("closed state reports %+v", state)
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket ограничивает частоту запросов к источнику.
+// Токены пополняются со скоростью rate в секунду до ёмкости burst.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket создаёт заполненный токен-бакет.
+func newTokenBucket(requestsPerMinute float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   requestsPerMinute / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill пополняет токены за время, прошедшее с последнего обращения. Вызывается под mu.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// Wait резервирует токен и ожидает, пока он станет доступен.
+//
+// Возвращает ошибку контекста, если ожидание прервано; резерв в этом случае возвращается.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	b.refill(time.Now())
+	b.tokens--
+	wait := time.Duration(0)
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Available возвращает текущее число доступных токенов (отрицательное — есть очередь ожидающих).
+func (b *tokenBucket) Available() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	return b.tokens
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketRefill(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{name: "no time passed", tokens: 1, want: 1},
+		{name: "one token per second at 60 rpm", tokens: 0, elapsed: 2500 * time.Millisecond, want: 2.5},
+		{name: "capped at burst", tokens: 2, elapsed: time.Minute, want: 5},
+		{name: "pays back reservations", tokens: -3, elapsed: 2 * time.Second, want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(60, 5)
+			b.tokens = tt.tokens
+			b.refill(b.last.Add(tt.elapsed))
+			if math.Abs(b.tokens-tt.want) > 1e-9 {
+				t.Errorf("tokens = %v, want %v", b.tokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := newTokenBucket(60, 2)
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("burst request %d: %v", i, err)
+		}
+	}
+
+	// Бакет пуст: следующий токен появится через секунду, ожидание прерывается контекстом и резерв возвращается.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want DeadlineExceeded", err)
+	}
+	if available := b.Available(); available < 0 || available > 0.1 {
+		t.Errorf("available = %v after cancelled wait, want about 0", available)
+	}
+}
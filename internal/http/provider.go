@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+)
+
+const (
+	ProviderCoinGecko = "coingecko"
+	ProviderBinance   = "binance"
+	ProviderKraken    = "kraken"
+	ProviderCoinCap   = "coincap"
+
+	// Размеры пакетов по умолчанию, если BATCH_SIZE не задан в конфигурации.
+	coinGeckoBatchSize = 100
+	binanceBatchSize   = 100
+	krakenBatchSize    = 1
+	coinCapBatchSize   = 100
+)
+
+// baseProvider содержит общие для всех адаптеров поля и сопоставление символов.
+type baseProvider struct {
+	transport *Transport
+	cfg       *config.ProviderConf
+}
+
+// Name возвращает имя источника из конфигурации.
+func (b *baseProvider) Name() string {
+	return strings.ToLower(b.cfg.Name)
+}
+
+// Budget возвращает состояние лимитов запросов к источнику.
+func (b *baseProvider) Budget() *models.ProviderBudget {
+	return b.transport.Budget()
+}
+
+// batchSize возвращает BATCH_SIZE из конфигурации или значение адаптера по умолчанию.
+func (b *baseProvider) batchSize(def int) int {
+	if b.cfg.BatchSize > common.Zero {
+		return b.cfg.BatchSize
+	}
+	return def
+}
+
+// symbol возвращает символ монеты у источника.
+//
+// Если монета не указана в SYMBOLS и fallback = true, используется сам ID монеты,
+// иначе возвращается ErrSymbolNotMapped.
+func (b *baseProvider) symbol(coin string, fallback bool) (string, error) {
+	if s, ok := b.cfg.Symbols[strings.ToLower(coin)]; ok {
+		return s, nil
+	}
+	if fallback {
+		return strings.ToLower(coin), nil
+	}
+	return common.Empty, fmt.Errorf("%w: %s/%s", common.ErrSymbolNotMapped, b.Name(), coin)
+}
+
+// quote возвращает актив котировки источника для указанной валюты и признак того,
+// что валюта указана в QUOTES. Для неуказанных валют возвращается сама валюта.
+func (b *baseProvider) quote(currency string) (string, bool) {
+	if q, ok := b.cfg.Quotes[strings.ToUpper(currency)]; ok {
+		return q, true
+	}
+	return strings.ToUpper(currency), false
+}
+
+// singlePrice получает цены одной монеты через пакетный запрос источника.
+func singlePrice(ctx context.Context, provider interfaces.PriceProviderI, coin string, currencies []string) (models.CoinPrice, error) {
+	prices, err := provider.CurrentDataMany(ctx, []string{coin}, currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	price, ok := prices[coin]
+	if !ok || len(price) == common.Zero {
+		return nil, fmt.Errorf("%w: %s has no price for %s", common.ErrUnexpectedPayload, provider.Name(), coin)
+	}
+	return price, nil
+}
+
+// chunkCoins разбивает список монет на части размером не больше size.
+func chunkCoins(coins []string, size int) [][]string {
+	if size <= common.Zero {
+		size = 1
+	}
+
+	chunks := make([][]string, 0, (len(coins)+size-1)/size)
+	for start := 0; start < len(coins); start += size {
+		end := min(start+size, len(coins))
+		chunks = append(chunks, coins[start:end])
+	}
+	return chunks
+}
+
+// newProvider создаёт адаптер источника цен по имени из конфигурации.
+func newProvider(cfg *config.ProviderConf, transport *Transport) (interfaces.PriceProviderI, error) {
+	base := baseProvider{
+		transport: transport,
+		cfg:       cfg,
+	}
+
+	switch strings.ToLower(cfg.Name) {
+	case ProviderCoinGecko:
+		return &coinGeckoProvider{baseProvider: base}, nil
+	case ProviderBinance:
+		return &binanceProvider{baseProvider: base}, nil
+	case ProviderKraken:
+		return &krakenProvider{baseProvider: base}, nil
+	case ProviderCoinCap:
+		return &coinCapProvider{baseProvider: base}, nil
+	default:
+		return nil, fmt.Errorf("unknown price provider %q", cfg.Name)
+	}
+}
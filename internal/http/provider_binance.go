@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// binanceProvider получает цены через Binance /api/v3/ticker/price.
+// Пара формируется как SYMBOLS[coin] + QUOTES[currency], например BTC + USDT.
+// Валюты без записи в QUOTES не запрашиваются.
+//
+// Binance отклоняет весь запрос, если хотя бы одна пара в symbols не существует. В этом случае
+// пары запрашиваются по одной, а отклонённые запоминаются и больше не запрашиваются до перезапуска.
+type binanceProvider struct {
+	baseProvider
+
+	mu      sync.Mutex
+	invalid map[string]struct{}
+}
+
+// binanceTicker содержит цену одной торговой пары из ответа Binance.
+type binanceTicker struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+// binancePair связывает торговую пару Binance с монетой и валютой котировки.
+type binancePair struct {
+	coin     string
+	currency string
+}
+
+// BatchSize возвращает максимальное число монет в одном запросе.
+func (p *binanceProvider) BatchSize() int {
+	return p.batchSize(binanceBatchSize)
+}
+
+// CurrentData получает текущие цены монеты в указанных валютах.
+func (p *binanceProvider) CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error) {
+	if _, err := p.symbol(coin, false); err != nil {
+		return nil, err
+	}
+	return singlePrice(ctx, p, coin, currencies)
+}
+
+// CurrentDataMany получает текущие цены нескольких монет одним запросом (symbols=[...]).
+// Монеты без записи в SYMBOLS пропускаются.
+func (p *binanceProvider) CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string]models.CoinPrice, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimePrice)
+	defer cancel()
+
+	pairs := make(map[string]binancePair, len(coins)*len(currencies))
+	list := make([]string, 0, len(coins)*len(currencies))
+	for _, coin := range coins {
+		base, err := p.symbol(coin, false)
+		if errors.Is(err, common.ErrSymbolNotMapped) {
+			continue
+		}
+		for _, currency := range currencies {
+			quote, ok := p.quote(currency)
+			if !ok || quote == base || p.isInvalid(base+quote) {
+				continue
+			}
+			pairs[base+quote] = binancePair{coin: coin, currency: currency}
+			list = append(list, base+quote)
+		}
+	}
+	if len(list) == common.Zero {
+		return map[string]models.CoinPrice{}, nil
+	}
+
+	tickers, err := p.tickers(ctxWithTimeout, list)
+	if errors.Is(err, common.ErrUnexpectedStatus) && len(list) > 1 {
+		tickers, err = p.tickersByPair(ctxWithTimeout, list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]models.CoinPrice, len(coins))
+	for _, ticker := range tickers {
+		pair, ok := pairs[ticker.Symbol]
+		if !ok {
+			continue
+		}
+		price, err := decimal.NewFromString(ticker.Price)
+		if err != nil {
+			zap.L().Warn("Skipping provider pair with invalid price", zap.String("provider:", p.Name()), zap.String("pair:", ticker.Symbol), zap.String("price:", ticker.Price))
+			continue
+		}
+		if prices[pair.coin] == nil {
+			prices[pair.coin] = make(models.CoinPrice, len(currencies))
+		}
+		prices[pair.coin][pair.currency] = &models.MarketQuote{Price: price}
+	}
+	return prices, nil
+}
+
+// tickers запрашивает цены указанных торговых пар одним запросом.
+func (p *binanceProvider) tickers(ctx context.Context, list []string) ([]binanceTicker, error) {
+	symbols, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("symbols", string(symbols))
+
+	body, err := p.transport.Get(ctx, fmt.Sprintf("%s/api/v3/ticker/price?%s", p.cfg.Url, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickers []binanceTicker
+	if err = json.Unmarshal(body, &tickers); err != nil {
+		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err), zap.String("provider:", p.Name()))
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// tickersByPair запрашивает торговые пары по одной после того, как Binance отклонил пакетный запрос.
+// Отклонённые пары пропускаются и запоминаются, остальные ошибки прерывают запрос.
+func (p *binanceProvider) tickersByPair(ctx context.Context, list []string) ([]binanceTicker, error) {
+	result := make([]binanceTicker, 0, len(list))
+	for _, symbol := range list {
+		tickers, err := p.tickers(ctx, []string{symbol})
+		if errors.Is(err, common.ErrUnexpectedStatus) {
+			zap.L().Warn("Skipping invalid provider pair", zap.Error(err), zap.String("provider:", p.Name()), zap.String("pair:", symbol))
+			p.markInvalid(symbol)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tickers...)
+	}
+	return result, nil
+}
+
+// isInvalid сообщает, была ли торговая пара ранее отклонена источником.
+func (p *binanceProvider) isInvalid(symbol string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.invalid[symbol]
+	return ok
+}
+
+// markInvalid запоминает торговую пару, отклонённую источником.
+func (p *binanceProvider) markInvalid(symbol string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.invalid == nil {
+		p.invalid = make(map[string]struct{})
+	}
+	p.invalid[symbol] = struct{}{}
+}
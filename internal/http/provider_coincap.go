@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// coinCapProvider получает цены через CoinCap /assets.
+// ID актива CoinCap совпадает с ID CoinGecko для большинства монет, поэтому SYMBOLS нужен только для исключений.
+// CoinCap отдаёт цену, капитализацию, объём и изменение за 24 часа только в USD, остальные валюты не запрашиваются.
+type coinCapProvider struct {
+	baseProvider
+}
+
+// BatchSize возвращает максимальное число монет в одном запросе.
+func (p *coinCapProvider) BatchSize() int {
+	return p.batchSize(coinCapBatchSize)
+}
+
+// CurrentData получает текущую цену монеты в USD.
+func (p *coinCapProvider) CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error) {
+	return singlePrice(ctx, p, coin, currencies)
+}
+
+// CurrentDataMany получает текущие цены нескольких монет в USD одним запросом (ids=a,b,c).
+func (p *coinCapProvider) CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string]models.CoinPrice, error) {
+	if !slices.Contains(currencies, common.DefaultCurrency) {
+		return map[string]models.CoinPrice{}, nil
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimePrice)
+	defer cancel()
+
+	ids := make(map[string]string, len(coins))
+	list := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		id, err := p.symbol(coin, true)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = coin
+		list = append(list, id)
+	}
+
+	query := url.Values{}
+	query.Set("ids", strings.Join(list, ","))
+
+	body, err := p.transport.Get(ctxWithTimeout, fmt.Sprintf("%s/assets?%s", p.cfg.Url, query.Encode()), p.headers())
+	if err != nil {
+		return nil, err
+	}
+
+	// Числа передаются строками, необязательные показатели могут быть null. Поле timestamp ответа — время
+	// ответа, а не обновления данных, поэтому UpdatedAt не заполняется.
+	var response struct {
+		Data []struct {
+			ID                string  `json:"id"`
+			PriceUsd          string  `json:"priceUsd"`
+			MarketCapUsd      *string `json:"marketCapUsd"`
+			VolumeUsd24Hr     *string `json:"volumeUsd24Hr"`
+			ChangePercent24Hr *string `json:"changePercent24Hr"`
+		} `json:"data"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err), zap.String("provider:", p.Name()))
+		return nil, err
+	}
+
+	prices := make(map[string]models.CoinPrice, len(response.Data))
+	for _, asset := range response.Data {
+		coin, ok := ids[asset.ID]
+		if !ok {
+			continue
+		}
+		price, err := decimal.NewFromString(asset.PriceUsd)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s price %q", common.ErrUnexpectedPayload, p.Name(), asset.PriceUsd)
+		}
+		prices[coin] = models.CoinPrice{common.DefaultCurrency: &models.MarketQuote{
+			Price: price,
+			MarketData: models.MarketData{
+				MarketCap: optionalDecimal(asset.MarketCapUsd),
+				Volume24h: optionalDecimal(asset.VolumeUsd24Hr),
+				Change24h: optionalDecimal(asset.ChangePercent24Hr),
+			},
+		}}
+	}
+	return prices, nil
+}
+
+// optionalDecimal разбирает необязательный числовой показатель; null и нечисловые значения дают nil.
+func optionalDecimal(value *string) *models.Decimal {
+	if value == nil {
+		return nil
+	}
+	result, err := decimal.NewFromString(*value)
+	if err != nil {
+		return nil
+	}
+	return models.NewDecimalPtr(&result)
+}
+
+// headers возвращает заголовок авторизации, если задан ключ API.
+func (p *coinCapProvider) headers() map[string]string {
+	if p.cfg.ApiKey == common.Empty {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.cfg.ApiKey}
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// coinGeckoProvider получает цены через CoinGecko /simple/price,
+// историю цен — через /coins/{id}/market_chart/range.
+type coinGeckoProvider struct {
+	baseProvider
+}
+
+// BatchSize возвращает максимальное число монет в одном запросе.
+func (p *coinGeckoProvider) BatchSize() int {
+	return p.batchSize(coinGeckoBatchSize)
+}
+
+// CurrentData получает текущие цены монеты в указанных валютах.
+func (p *coinGeckoProvider) CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error) {
+	return singlePrice(ctx, p, coin, currencies)
+}
+
+// CurrentDataMany получает текущие цены нескольких монет одним запросом (ids=a,b,c&vs_currencies=usd,eur)
+// вместе с капитализацией, объёмом и изменением за 24 часа в каждой валюте и временем обновления данных.
+func (p *coinGeckoProvider) CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string]models.CoinPrice, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimePrice)
+	defer cancel()
+
+	ids := make(map[string]string, len(coins))
+	list := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		id, err := p.symbol(coin, true)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = coin
+		list = append(list, id)
+	}
+
+	vsCurrencies := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		vsCurrencies = append(vsCurrencies, strings.ToLower(currency))
+	}
+
+	query := url.Values{}
+	query.Set("ids", strings.Join(list, ","))
+	query.Set("vs_currencies", strings.Join(vsCurrencies, ","))
+	query.Set("include_market_cap", "true")
+	query.Set("include_24hr_vol", "true")
+	query.Set("include_24hr_change", "true")
+	query.Set("include_last_updated_at", "true")
+
+	body, err := p.transport.Get(ctxWithTimeout, fmt.Sprintf("%s/simple/price?%s", p.cfg.Url, query.Encode()), p.headers())
+	if err != nil {
+		return nil, err
+	}
+
+	// decimal.Decimal разбирает числа JSON по исходному тексту, без промежуточного float64.
+	// Ключи монеты: "usd", "usd_market_cap", "usd_24h_vol", "usd_24h_change" и "last_updated_at";
+	// отсутствующие у CoinGecko показатели приходят как null.
+	priceResponse := make(map[string]map[string]*decimal.Decimal)
+
+	if err = json.Unmarshal(body, &priceResponse); err != nil {
+		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err), zap.String("provider:", p.Name()))
+		return nil, err
+	}
+
+	prices := make(map[string]models.CoinPrice, len(priceResponse))
+	for id, coin := range ids {
+		values, ok := priceResponse[id]
+		if !ok {
+			continue
+		}
+		var updatedAt int64
+		if value := values["last_updated_at"]; value != nil {
+			updatedAt = value.IntPart()
+		}
+
+		price := make(models.CoinPrice, len(vsCurrencies))
+		for _, currency := range vsCurrencies {
+			value := values[currency]
+			if value == nil {
+				continue
+			}
+			price[strings.ToUpper(currency)] = &models.MarketQuote{
+				Price: *value,
+				MarketData: models.MarketData{
+					MarketCap: models.NewDecimalPtr(values[currency+"_market_cap"]),
+					Volume24h: models.NewDecimalPtr(values[currency+"_24h_vol"]),
+					Change24h: models.NewDecimalPtr(values[currency+"_24h_change"]),
+				},
+				UpdatedAt: updatedAt,
+			}
+		}
+		prices[coin] = price
+	}
+	return prices, nil
+}
+
+// PriceRange получает историю цен монеты в валюте за период [from, to] (unix, сек).
+//
+// Детализация определяется CoinGecko по длине периода: до 90 дней — часовые точки,
+// больше — дневные. Точки вне периода отбрасываются, метки времени переводятся в секунды.
+func (p *coinGeckoProvider) PriceRange(ctx context.Context, coin, currency string, from, to int64) ([]*models.PricePoint, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimeHistory)
+	defer cancel()
+
+	id, err := p.symbol(coin, true)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("vs_currency", strings.ToLower(currency))
+	query.Set("from", strconv.FormatInt(from, 10))
+	query.Set("to", strconv.FormatInt(to, 10))
+
+	body, err := p.transport.Get(ctxWithTimeout, fmt.Sprintf("%s/coins/%s/market_chart/range?%s", p.cfg.Url, url.PathEscape(id), query.Encode()), p.headers())
+	if err != nil {
+		return nil, err
+	}
+
+	// Каждая точка — пара [метка времени в мс, цена].
+	var chart struct {
+		Prices [][2]decimal.Decimal `json:"prices"`
+	}
+
+	if err = json.Unmarshal(body, &chart); err != nil {
+		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err), zap.String("provider:", p.Name()))
+		return nil, err
+	}
+
+	points := make([]*models.PricePoint, 0, len(chart.Prices))
+	for _, item := range chart.Prices {
+		timestamp := item[0].IntPart() / 1000
+		if timestamp < from || timestamp > to {
+			continue
+		}
+		points = append(points, &models.PricePoint{
+			Timestamp: timestamp,
+			Price:     item[1],
+		})
+	}
+	return points, nil
+}
+
+// HistoryStep возвращает интервал часовых точек, которые CoinGecko отдаёт за период до 90 дней.
+func (p *coinGeckoProvider) HistoryStep() int64 {
+	return common.CoinGeckoHistoryStep
+}
+
+// headers возвращает заголовок с demo-ключом API, если он задан.
+func (p *coinGeckoProvider) headers() map[string]string {
+	if p.cfg.ApiKey == common.Empty {
+		return nil
+	}
+	return map[string]string{"x-cg-demo-api-key": p.cfg.ApiKey}
+}
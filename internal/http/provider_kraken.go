@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// krakenProvider получает цены через Kraken /0/public/Ticker.
+// Пара формируется как SYMBOLS[coin] + QUOTES[currency], например XBT + USD.
+// Валюты без записи в QUOTES не запрашиваются.
+type krakenProvider struct {
+	baseProvider
+}
+
+// krakenTicker описывает нужную часть ответа Kraken: c[0] — цена последней сделки.
+type krakenTicker struct {
+	Close []string `json:"c"`
+}
+
+// BatchSize возвращает максимальное число монет в одном запросе.
+//
+// Kraken возвращает пары под внутренними именами (XBTUSD -> XXBTZUSD), которые нельзя надёжно
+// сопоставить с запрошенными, поэтому по умолчанию каждая монета запрашивается отдельно.
+func (p *krakenProvider) BatchSize() int {
+	return p.batchSize(krakenBatchSize)
+}
+
+// CurrentDataMany получает текущие цены монет, выполняя по запросу на каждую монету.
+// Монеты без записи в SYMBOLS пропускаются, ошибка возвращается, только если не получено ни одной цены.
+func (p *krakenProvider) CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string]models.CoinPrice, error) {
+	prices := make(map[string]models.CoinPrice, len(coins))
+
+	var errs []error
+	for _, coin := range coins {
+		price, err := p.CurrentData(ctx, coin, currencies)
+		if err != nil {
+			if !errors.Is(err, common.ErrSymbolNotMapped) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if len(price) > common.Zero {
+			prices[coin] = price
+		}
+	}
+
+	if len(prices) == common.Zero && len(errs) > common.Zero {
+		return nil, errors.Join(errs...)
+	}
+	return prices, nil
+}
+
+// CurrentData получает текущие цены монеты в указанных валютах, по запросу на каждую пару.
+// Неудачные пары пропускаются, ошибка возвращается, только если не получено ни одной цены.
+func (p *krakenProvider) CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error) {
+	base, err := p.symbol(coin, false)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(models.CoinPrice, len(currencies))
+	var errs []error
+	for _, currency := range currencies {
+		quote, ok := p.quote(currency)
+		if !ok || quote == base {
+			continue
+		}
+		price, err := p.pairPrice(ctx, coin, base+quote)
+		if err != nil {
+			zap.L().Warn("Skipping failed provider pair", zap.Error(err), zap.String("provider:", p.Name()), zap.String("pair:", base+quote))
+			errs = append(errs, err)
+			continue
+		}
+		prices[currency] = &models.MarketQuote{Price: price}
+	}
+
+	if len(prices) == common.Zero && len(errs) > common.Zero {
+		return nil, errors.Join(errs...)
+	}
+	return prices, nil
+}
+
+// pairPrice получает цену последней сделки по паре.
+func (p *krakenProvider) pairPrice(ctx context.Context, coin, pair string) (decimal.Decimal, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimePrice)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("pair", pair)
+
+	body, err := p.transport.Get(ctxWithTimeout, fmt.Sprintf("%s/0/public/Ticker?%s", p.cfg.Url, query.Encode()), nil)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	var response struct {
+		Error  []string                 `json:"error"`
+		Result map[string]*krakenTicker `json:"result"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err), zap.String("provider:", p.Name()))
+		return decimal.Zero, err
+	}
+	if len(response.Error) > common.Zero {
+		return decimal.Zero, fmt.Errorf("%w: %s: %s", common.ErrUnexpectedPayload, p.Name(), strings.Join(response.Error, "; "))
+	}
+
+	// Kraken возвращает пару под своим внутренним именем (например, XXBTZUSD), поэтому берём единственный результат.
+	for _, ticker := range response.Result {
+		if ticker == nil || len(ticker.Close) == common.Zero {
+			break
+		}
+		price, err := decimal.NewFromString(ticker.Close[0])
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("%w: %s price %q", common.ErrUnexpectedPayload, p.Name(), ticker.Close[0])
+		}
+		return price, nil
+	}
+	return decimal.Zero, fmt.Errorf("%w: %s has no price for %s", common.ErrUnexpectedPayload, p.Name(), coin)
+}
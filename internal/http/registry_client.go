@@ -4,136 +4,300 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testYTask/internal/common"
 	"testYTask/internal/config"
+	"testYTask/internal/domain/interfaces"
 	"testYTask/internal/domain/models"
 
 	"go.uber.org/zap"
 )
 
-// RegistryClient отвечает за отправку HTTP-запросов к внешним сервисам
+// RegistryClient отвечает за отправку HTTP-запросов к внешним сервисам.
+// Хранит набор источников цен и опрашивает их в порядке приоритета.
+// У каждого источника свой Transport с собственным токен-бакетом.
 type RegistryClient struct {
-	httpClient *http.Client
-	cfg        *config.ApiExchange
+	cfg       *config.ApiExchange
+	catalog   *Transport
+	providers []interfaces.PriceProviderI
 }
 
 // NewRegistryClient создаёт и инициализирует новый экземпляр RegistryClient с заданной конфигурацией.
+//
+// Источники с ENABLED = false и источники неизвестного типа пропускаются.
 func NewRegistryClient(cfg *config.ApiExchange) *RegistryClient {
-	return &RegistryClient{
-		httpClient: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
 			},
-			Timeout: common.RequestTimeout,
 		},
-		cfg: cfg,
+		Timeout: common.RequestTimeout,
+	}
+
+	enabled := make([]*config.ProviderConf, 0, len(cfg.Providers))
+	for _, providerCfg := range cfg.Providers {
+		if providerCfg != nil && providerCfg.Enabled {
+			enabled = append(enabled, providerCfg)
+		}
+	}
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].Priority < enabled[j].Priority
+	})
+
+	var catalog *Transport
+
+	providers := make([]interfaces.PriceProviderI, 0, len(enabled))
+	for _, providerCfg := range enabled {
+		transport := NewTransport(strings.ToLower(providerCfg.Name), httpClient, providerCfg.RateLimit, providerCfg.Breaker)
+		provider, err := newProvider(providerCfg, transport)
+		if err != nil {
+			zap.L().Error("Price provider skipped", zap.Error(err))
+			continue
+		}
+		providers = append(providers, provider)
+		zap.L().Info("Price provider enabled", zap.String("provider:", provider.Name()), zap.Int("priority:", providerCfg.Priority))
+
+		// Список монет загружается с CoinGecko, поэтому запросы каталога расходуют его лимит.
+		if provider.Name() == ProviderCoinGecko {
+			catalog = transport
+		}
+	}
+	if catalog == nil {
+		catalog = NewTransport(ProviderCoinGecko, httpClient, nil, nil)
 	}
+
+	return &RegistryClient{
+		cfg:       cfg,
+		catalog:   catalog,
+		providers: providers,
+	}
+}
+
+// Providers возвращает включённые источники цен в порядке приоритета.
+func (r *RegistryClient) Providers() []interfaces.PriceProviderI {
+	return r.providers
 }
 
-// LoadValidCoins загружает список всех валидных монет с внешнего API.
+// HistoryProvider возвращает первый по приоритету источник, поддерживающий загрузку истории цен.
+//
+// Возвращает ErrNoHistoryProvider, если среди включённых источников такого нет.
+func (r *RegistryClient) HistoryProvider() (interfaces.HistoryProviderI, error) {
+	for _, provider := range r.providers {
+		if history, ok := provider.(interfaces.HistoryProviderI); ok {
+			return history, nil
+		}
+	}
+	return nil, common.ErrNoHistoryProvider
+}
+
+// Budgets возвращает состояние лимитов запросов всех включённых источников.
+func (r *RegistryClient) Budgets() []*models.ProviderBudget {
+	budgets := make([]*models.ProviderBudget, 0, len(r.providers))
+	for _, provider := range r.providers {
+		budgets = append(budgets, provider.Budget())
+	}
+	return budgets
+}
+
+// HealthReport возвращает состояние источников цен для подробного ответа /healthz.
+// Компонент считается работоспособным, если хотя бы у одного источника автомат защиты не разомкнут.
+func (r *RegistryClient) HealthReport() *models.HealthComponent {
+	budgets := r.Budgets()
+
+	healthy := false
+	for _, budget := range budgets {
+		if budget.Circuit == nil || budget.Circuit.State != BreakerOpen {
+			healthy = true
+			break
+		}
+	}
+	return &models.HealthComponent{
+		Name:    "providers",
+		Healthy: healthy,
+		Detail:  budgets,
+	}
+}
+
+// LoadValidCoins загружает список валидных монет с внешнего API постранично.
+//
+// Параметр page адреса URL_LIST_COIN перебирается с 1, пока страница не окажется неполной
+// (меньше per_page монет) или не будет загружено LIST_COIN_PAGES страниц. Ошибка любой страницы
+// прерывает загрузку, чтобы неполный список не заменил текущий.
 //
 // Параметры:
 //   - ctx: контекст запроса для управления временем выполнения и отменой.
 //
 // Возвращает:
-//   - map[string]bool: словарь. Ключ - название монеты, значение - true.
+//   - []*models.CoinInfo: монеты в порядке ответа источника без повторов.
 //   - error: ошибка при получении или обработке данных.
-func (r *RegistryClient) LoadValidCoins(ctx context.Context) (map[string]bool, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimeValidCoins)
-	defer cancel()
-
-	var coins []struct {
-		ID     string `json:"id"`
-		Symbol string `json:"symbol"`
-		Name   string `json:"name"`
-	}
-
-	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodGet, r.cfg.UrlListCoins, nil)
+func (r *RegistryClient) LoadValidCoins(ctx context.Context) ([]*models.CoinInfo, error) {
+	listURL, err := url.Parse(r.cfg.UrlListCoins)
 	if err != nil {
-		zap.L().Error("Error creating HTTP request", zap.Error(err))
+		zap.L().Error("Invalid URL_LIST_COIN", zap.Error(err))
 		return nil, err
 	}
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		zap.L().Error("Error executing HTTP request", zap.Error(err))
-		return nil, err
+	query := listURL.Query()
+
+	perPage := common.DefaultCoinsPerPage
+	if value, err := strconv.Atoi(query.Get("per_page")); err == nil && value > common.Zero {
+		perPage = value
+	}
+	pages := common.DefaultListCoinPages
+	if r.cfg.ListCoinPages > common.Zero {
+		pages = r.cfg.ListCoinPages
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		zap.L().Error("Error reading HTTP response body", zap.Error(err))
-		return nil, err
+	seen := make(map[string]bool)
+	coins := make([]*models.CoinInfo, 0, perPage)
+	for page := 1; page <= pages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		listURL.RawQuery = query.Encode()
+
+		batch, err := r.loadCoinsPage(ctx, listURL.String())
+		if err != nil {
+			zap.L().Error("Error loading coin list page", zap.Error(err), zap.Int("page:", page))
+			return nil, err
+		}
+		for _, coin := range batch {
+			if coin.ID == common.Empty || seen[coin.ID] {
+				continue
+			}
+			seen[coin.ID] = true
+			coins = append(coins, coin)
+		}
+		if len(batch) < perPage {
+			break
+		}
 	}
+	return coins, nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		errMsg := fmt.Sprintf("Unexpected status code: %d", resp.StatusCode)
+// loadCoinsPage загружает одну страницу списка монет.
+func (r *RegistryClient) loadCoinsPage(ctx context.Context, pageURL string) ([]*models.CoinInfo, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimeValidCoins)
+	defer cancel()
 
-		zap.L().Error(errMsg)
-		return nil, fmt.Errorf(errMsg)
+	body, err := r.catalog.Get(ctxWithTimeout, pageURL, nil)
+	if err != nil {
+		return nil, err
 	}
 
+	var coins []*models.CoinInfo
 	if err = json.Unmarshal(body, &coins); err != nil {
 		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err))
 		return nil, err
 	}
-
-	validCoins := make(map[string]bool)
-
-	for _, c := range coins {
-		validCoins[c.ID] = true
-	}
-	return validCoins, nil
+	return coins, nil
 }
 
-// CurrentData получает текущие данные о цене указанной монеты с внешнего API.
+// CurrentData получает текущие данные о цене указанной монеты.
+//
+// Источники опрашиваются по приоритету, возвращается первый успешный ответ.
+// Если ни один источник не ответил, возвращаются ошибки всех источников.
 //
 // Параметры:
 //   - ctx: контекст запроса для управления временем выполнения и отменой.
 //   - coin: название монеты (например, "bitcoin").
+//   - currencies: валюты котировки (например, ["USD", "EUR"]).
 //
 // Возвращает:
-//   - *models.CoinPrice: структура с ценой монеты, валютой и временем получения.
+//   - models.CoinPrice: котировки монеты по валютам.
 //   - error: ошибка при получении или обработке данных.
-func (r *RegistryClient) CurrentData(ctx context.Context, coin string) (*models.CoinPrice, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, common.ReqTimePrice)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodGet, fmt.Sprintf(r.cfg.Url, coin), nil)
-	if err != nil {
-		zap.L().Error("Error creating HTTP request", zap.Error(err))
-		return nil, err
+func (r *RegistryClient) CurrentData(ctx context.Context, coin string, currencies []string) (models.CoinPrice, error) {
+	if len(r.providers) == common.Zero {
+		return nil, common.ErrNoProviders
 	}
 
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		zap.L().Error("Error executing HTTP request", zap.Error(err))
-		return nil, err
+	var errs []error
+	for _, provider := range r.providers {
+		data, err := provider.CurrentData(ctx, coin, currencies)
+		if err == nil && len(data) > common.Zero {
+			return data, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%w: %s has no price for %s", common.ErrUnexpectedPayload, provider.Name(), coin)
+		}
+		if !errors.Is(err, common.ErrSymbolNotMapped) {
+			zap.L().Warn("Price provider failed, trying next", zap.Error(err), zap.String("provider:", provider.Name()), zap.String("name:", coin))
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
 	}
-	defer resp.Body.Close()
+	return nil, errors.Join(errs...)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		zap.L().Error("Error reading HTTP response body", zap.Error(err))
-		return nil, err
+// CurrentDataMany параллельно запрашивает цены списка монет у всех включённых источников.
+//
+// Для каждого источника список разбивается на пакеты размером BatchSize, пакеты одного
+// источника запрашиваются последовательно. Ошибка пакета не прерывает остальные пакеты.
+// Каждая монета запрашивается во всех переданных валютах.
+//
+// Возвращает:
+//   - map[string][]*models.ProviderQuote: котировки по монетам в порядке приоритета источников и валют.
+//   - error: объединённые ошибки источников, если не получено ни одной котировки.
+func (r *RegistryClient) CurrentDataMany(ctx context.Context, coins []string, currencies []string) (map[string][]*models.ProviderQuote, error) {
+	if len(r.providers) == common.Zero {
+		return nil, common.ErrNoProviders
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		errMsg := fmt.Sprintf("Unexpected status code: %d", resp.StatusCode)
 
-		zap.L().Error(errMsg)
-		return nil, fmt.Errorf(errMsg)
-	}
+	results := make([]map[string]models.CoinPrice, len(r.providers))
+	errs := make([]error, len(r.providers))
 
-	priceResponse := make(map[string]*models.CoinPrice)
+	var wg sync.WaitGroup
+	for i, provider := range r.providers {
+		wg.Add(1)
+		go func(i int, provider interfaces.PriceProviderI) {
+			defer wg.Done()
 
-	if err = json.Unmarshal(body, &priceResponse); err != nil {
-		zap.L().Error("Error unmarshalling HTTP response body", zap.Error(err))
-		return nil, err
+			prices := make(map[string]models.CoinPrice, len(coins))
+			for _, batch := range chunkCoins(coins, provider.BatchSize()) {
+				data, err := provider.CurrentDataMany(ctx, batch, currencies)
+				if errors.Is(err, common.ErrCircuitOpen) {
+					// Источник недоступен: остальные пакеты всё равно будут отклонены.
+					errs[i] = errors.Join(errs[i], fmt.Errorf("%s: %w", provider.Name(), err))
+					break
+				}
+				if err != nil {
+					zap.L().Warn("Price provider batch failed", zap.Error(err), zap.String("provider:", provider.Name()), zap.Int("size:", len(batch)))
+					errs[i] = errors.Join(errs[i], fmt.Errorf("%s: %w", provider.Name(), err))
+					continue
+				}
+				for coin, price := range data {
+					prices[coin] = price
+				}
+			}
+			results[i] = prices
+		}(i, provider)
+	}
+	wg.Wait()
+
+	quotes := make(map[string][]*models.ProviderQuote, len(coins))
+	for i, provider := range r.providers {
+		for coin, price := range results[i] {
+			for _, currency := range currencies {
+				value, ok := price[currency]
+				if !ok {
+					continue
+				}
+				quotes[coin] = append(quotes[coin], &models.ProviderQuote{
+					Provider:   provider.Name(),
+					Currency:   currency,
+					Price:      value.Price,
+					MarketData: value.MarketData,
+					UpdatedAt:  value.UpdatedAt,
+				})
+			}
+		}
+	}
+	if len(quotes) == common.Zero && len(coins) > common.Zero {
+		return nil, errors.Join(errs...)
 	}
-	return priceResponse[coin], nil
+	return quotes, nil
 }
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errProviderBlocked отмечает запросы, отклонённые блокировкой источника без обращения к нему.
+var errProviderBlocked = errors.New("blocked")
+
+// Transport выполняет запросы к одному источнику с учётом его лимитов.
+//
+// Перед каждым запросом ожидается токен из токен-бакета. Ответы 429 и 5xx, а также сетевые
+// ошибки повторяются с экспоненциальной задержкой и случайным джиттером; заголовок Retry-After
+// задаёт минимальную задержку. Если Retry-After превышает MaxDelayMs, источник блокируется до
+// указанного времени и запросы завершаются ErrRateLimited без обращения к источнику.
+//
+// Вызовы Get защищены автоматом circuitBreaker: пока источник считается недоступным,
+// запросы сразу завершаются ErrCircuitOpen.
+type Transport struct {
+	name       string
+	httpClient *http.Client
+	limiter    *tokenBucket
+	breaker    *circuitBreaker
+	cfg        *config.RateLimitConf
+	maxRetries int
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+	lastStatus   int
+
+	requests    atomic.Int64
+	retries     atomic.Int64
+	rateLimited atomic.Int64
+	failures    atomic.Int64
+}
+
+// NewTransport создаёт транспорт источника. Нулевые значения лимитов заменяются значениями по умолчанию,
+// число повторов — только если оно не задано (0 отключает повторы).
+//
+// Параметры:
+//   - name: имя источника (используется в логах и состоянии лимитов).
+//   - httpClient: общий HTTP-клиент.
+//   - cfg: лимиты и политика повторов (может быть nil).
+//   - breakerCfg: настройки автомата защиты (может быть nil).
+func NewTransport(name string, httpClient *http.Client, cfg *config.RateLimitConf, breakerCfg *config.BreakerConf) *Transport {
+	limits := config.RateLimitConf{}
+	if cfg != nil {
+		limits = *cfg
+	}
+	if limits.RequestsPerMinute <= common.Zero {
+		limits.RequestsPerMinute = common.DefaultRequestsPerMinute
+	}
+	if limits.Burst <= common.Zero {
+		limits.Burst = common.DefaultBurst
+	}
+	maxRetries := common.DefaultMaxRetries
+	if limits.MaxRetries != nil {
+		maxRetries = max(*limits.MaxRetries, common.Zero)
+	}
+	if limits.BaseDelayMs <= common.Zero {
+		limits.BaseDelayMs = int(common.DefaultRetryBaseDelay / time.Millisecond)
+	}
+	if limits.MaxDelayMs <= common.Zero {
+		limits.MaxDelayMs = int(common.DefaultRetryMaxDelay / time.Millisecond)
+	}
+
+	return &Transport{
+		name:       name,
+		httpClient: httpClient,
+		limiter:    newTokenBucket(limits.RequestsPerMinute, limits.Burst),
+		breaker:    newCircuitBreaker(breakerCfg),
+		cfg:        &limits,
+		maxRetries: maxRetries,
+	}
+}
+
+// Get выполняет GET-запрос к источнику с ожиданием лимита и повторами.
+//
+// Параметры:
+//   - ctx: контекст запроса, ограничивает и ожидание лимита, и повторы.
+//   - url: адрес запроса.
+//   - headers: дополнительные заголовки запроса (может быть nil).
+//
+// Возвращает:
+//   - []byte: тело ответа со статусом 2xx.
+//   - error: ErrCircuitOpen, ErrRateLimited, ErrUpstreamFailure, ErrUnexpectedStatus или ошибку сети/контекста.
+func (t *Transport) Get(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	// Отказы без обращения к источнику (блокировка по Retry-After, разомкнутый автомат) не считаются неудачами источника.
+	if until := t.blocked(); !until.IsZero() {
+		return nil, t.blockedError(until)
+	}
+
+	before := t.breaker.State().State
+	if err := t.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, t.name)
+	}
+
+	body, err := t.get(ctx, url, headers)
+	switch {
+	case err == nil, errors.Is(err, common.ErrUnexpectedStatus):
+		// Источник ответил, значит он доступен, даже если запрос был некорректным.
+		t.breaker.Success()
+	case ctx.Err() != nil, errors.Is(err, errProviderBlocked):
+		t.breaker.Cancel()
+	default:
+		t.breaker.Failure()
+	}
+
+	if after := t.breaker.State().State; after != before {
+		zap.L().Warn("Provider circuit breaker state changed", zap.String("provider:", t.name), zap.String("from:", before), zap.String("to:", after))
+	}
+	return body, err
+}
+
+// get выполняет запрос с ожиданием лимита и повторами, без учёта автомата защиты.
+func (t *Transport) get(ctx context.Context, url string, headers map[string]string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if until := t.blocked(); !until.IsZero() {
+			if attempt == common.Zero {
+				return nil, t.blockedError(until)
+			}
+			return nil, fmt.Errorf("%w: %s blocked until %s", common.ErrRateLimited, t.name, until.Format(time.RFC3339))
+		}
+
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		body, retryAfter, err := t.do(ctx, url, headers)
+		if err == nil {
+			return body, nil
+		}
+		if !retryable(ctx, err) {
+			t.failures.Add(1)
+			return nil, err
+		}
+
+		// Retry-After соблюдается и тогда, когда повторов больше нет: следующий запуск не должен обращаться
+		// к источнику раньше, чем он разрешил.
+		maxDelay := time.Duration(t.cfg.MaxDelayMs) * time.Millisecond
+		if retryAfter > maxDelay || (retryAfter > common.Zero && attempt >= t.maxRetries) {
+			t.block(time.Now().Add(retryAfter))
+			t.failures.Add(1)
+			zap.L().Warn("Provider rate limit exhausted", zap.String("provider:", t.name), zap.Duration("retry_after:", retryAfter))
+			return nil, err
+		}
+		if attempt >= t.maxRetries {
+			t.failures.Add(1)
+			return nil, err
+		}
+
+		delay := max(t.backoff(attempt), retryAfter)
+		t.retries.Add(1)
+		zap.L().Warn("Retrying provider request", zap.Error(err), zap.String("provider:", t.name), zap.Int("attempt:", attempt+1), zap.Duration("delay:", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			t.failures.Add(1)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Budget возвращает текущее состояние лимитов источника.
+func (t *Transport) Budget() *models.ProviderBudget {
+	t.mu.Lock()
+	blockedUntil, lastStatus := t.blockedUntil, t.lastStatus
+	t.mu.Unlock()
+
+	budget := &models.ProviderBudget{
+		Provider:          t.name,
+		RequestsPerMinute: t.cfg.RequestsPerMinute,
+		Burst:             t.cfg.Burst,
+		TokensAvailable:   t.limiter.Available(),
+		Requests:          t.requests.Load(),
+		Retries:           t.retries.Load(),
+		RateLimited:       t.rateLimited.Load(),
+		Failures:          t.failures.Load(),
+		LastStatus:        lastStatus,
+		Circuit:           t.breaker.State(),
+	}
+	if blockedUntil.After(time.Now()) {
+		budget.BlockedUntil = blockedUntil.Unix()
+	}
+	return budget
+}
+
+// do выполняет одну попытку запроса.
+//
+// Возвращает тело ответа, задержку из Retry-After (если есть) и ошибку.
+func (t *Transport) do(ctx context.Context, url string, headers map[string]string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		zap.L().Error("Error creating HTTP request", zap.Error(err))
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	t.requests.Add(1)
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		zap.L().Error("Error executing HTTP request", zap.Error(err), zap.String("provider:", t.name))
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	t.mu.Lock()
+	t.lastStatus = resp.StatusCode
+	t.mu.Unlock()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		zap.L().Error("Error reading HTTP response body", zap.Error(err), zap.String("provider:", t.name))
+		return nil, 0, err
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return body, 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		t.rateLimited.Add(1)
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("%w: %s status %d", common.ErrRateLimited, t.name, resp.StatusCode)
+	case resp.StatusCode >= 500:
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("%w: %s status %d", common.ErrUpstreamFailure, t.name, resp.StatusCode)
+	default:
+		zap.L().Error("Unexpected status code", zap.Int("status:", resp.StatusCode), zap.String("provider:", t.name), zap.String("url:", url))
+		return nil, 0, fmt.Errorf("%w: %s status %d", common.ErrUnexpectedStatus, t.name, resp.StatusCode)
+	}
+}
+
+// backoff возвращает задержку перед повтором: случайное значение в [d/2, d],
+// где d = BaseDelay * 2^attempt, ограниченное MaxDelay.
+func (t *Transport) backoff(attempt int) time.Duration {
+	base := time.Duration(t.cfg.BaseDelayMs) * time.Millisecond
+	maxDelay := time.Duration(t.cfg.MaxDelayMs) * time.Millisecond
+
+	delay := maxDelay
+	if attempt < 30 {
+		delay = min(base<<attempt, maxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// blockedError возвращает ошибку запроса, отклонённого до обращения к источнику из-за блокировки.
+func (t *Transport) blockedError(until time.Time) error {
+	return fmt.Errorf("%w: %w: %s until %s", common.ErrRateLimited, errProviderBlocked, t.name, until.Format(time.RFC3339))
+}
+
+// blocked возвращает время окончания блокировки источника или нулевое время.
+func (t *Transport) blocked() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.blockedUntil.After(time.Now()) {
+		return t.blockedUntil
+	}
+	return time.Time{}
+}
+
+// block блокирует запросы к источнику до указанного времени.
+func (t *Transport) block(until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until.After(t.blockedUntil) {
+		t.blockedUntil = until
+	}
+}
+
+// retryable сообщает, имеет ли смысл повторять запрос после ошибки.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, common.ErrUnexpectedStatus) {
+		return false
+	}
+	return true
+}
+
+// parseRetryAfter разбирает заголовок Retry-After в секундах или в формате HTTP-даты.
+func parseRetryAfter(value string) time.Duration {
+	if value == common.Empty {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > common.Zero {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
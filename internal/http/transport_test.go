@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		delta time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "zero seconds", value: "0", want: 0},
+		{name: "negative seconds", value: "-5", want: 0},
+		{name: "garbage", value: "soon", want: 0},
+		{name: "http date", value: time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), want: 90 * time.Second, delta: 2 * time.Second},
+		{name: "http date in the past", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if diff := got - tt.want; diff < -tt.delta || diff > tt.delta {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransportHonoursRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		retryAfter string
+		requests   int64
+	}{
+		{name: "retries disabled", maxRetries: 0, retryAfter: "30", requests: 1},
+		{name: "retries exhausted", maxRetries: 1, retryAfter: "1", requests: 2},
+		{name: "retry-after above max delay", maxRetries: 3, retryAfter: "30", requests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Retry-After", tt.retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+
+			maxRetries := tt.maxRetries
+			transport := NewTransport("test", server.Client(), &config.RateLimitConf{
+				RequestsPerMinute: 6000,
+				MaxRetries:        &maxRetries,
+				BaseDelayMs:       1,
+				MaxDelayMs:        1500,
+			}, nil)
+
+			if _, err := transport.Get(context.Background(), server.URL, nil); !errors.Is(err, common.ErrRateLimited) {
+				t.Fatalf("first Get() = %v, want ErrRateLimited", err)
+			}
+			if _, err := transport.Get(context.Background(), server.URL, nil); !errors.Is(err, common.ErrRateLimited) {
+				t.Fatalf("second Get() = %v, want ErrRateLimited", err)
+			}
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("provider got %d requests, want %d", got, tt.requests)
+			}
+			if transport.Budget().BlockedUntil < time.Now().Unix() {
+				t.Error("provider is not blocked until Retry-After")
+			}
+		})
+	}
+}
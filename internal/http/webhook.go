@@ -0,0 +1,114 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/models"
+	"time"
+)
+
+const (
+	HeaderSignature          = "X-Signature"
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	HeaderDeliveryID         = "X-Alert-Delivery"
+)
+
+// WebhookClient отправляет оповещения на вебхуки правил.
+//
+// Тело запроса — сохранённое оповещение (models.AlertEvent) в JSON. Заголовок X-Signature
+// содержит "sha256=" и HMAC-SHA256 в hex от строки "<X-Signature-Timestamp>.<тело>" с секретом
+// правила; получатель проверяет подпись и отбрасывает запросы со старой меткой времени.
+// Перенаправления не выполняются: ответ 3xx считается ошибкой доставки.
+//
+// Доставка на loopback, link-local, частные и другие внутренние адреса запрещена (см. webhookGuard),
+// кроме разрешённых в ALERTS.WEBHOOK_ALLOW_LIST; прокси из окружения не используется.
+type WebhookClient struct {
+	httpClient *http.Client
+	guard      *webhookGuard
+}
+
+// NewWebhookClient создаёт клиент доставки вебхуков.
+//
+// Параметры:
+//   - cfg: настройки оповещений с разрешёнными внутренними адресами (может быть nil).
+func NewWebhookClient(cfg *config.AlertsConf) *WebhookClient {
+	guard := newWebhookGuard(cfg)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = guard.dialContext
+
+	return &WebhookClient{
+		httpClient: &http.Client{
+			Timeout:   common.WebhookTimeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		guard: guard,
+	}
+}
+
+// Validate проверяет адрес вебхука: схема http или https, непустой хост и отсутствие внутренних адресов
+// среди адресов хоста.
+//
+// Возвращает нормализованный адрес или ошибку ErrWebhookBlocked.
+func (w *WebhookClient) Validate(ctx context.Context, rawURL string) (string, error) {
+	webhook, err := w.guard.validate(ctx, rawURL)
+	if err != nil {
+		return common.Empty, err
+	}
+	return webhook.String(), nil
+}
+
+// Send выполняет одну попытку доставки.
+//
+// Возвращает HTTP-код ответа (0, если ответа не было) и ошибку; для ответов вне 2xx — ErrWebhookRejected,
+// для запрещённого адреса — ErrWebhookBlocked.
+func (w *WebhookClient) Send(ctx context.Context, delivery *models.AlertDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.WebhookURL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return common.Zero, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(delivery.Secret, timestamp, delivery.Payload))
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return common.Zero, fmt.Errorf("%w: scheme %q", common.ErrWebhookBlocked, req.URL.Scheme)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return common.Zero, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("%w: %d", common.ErrWebhookRejected, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает HMAC-SHA256 в hex от строки "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
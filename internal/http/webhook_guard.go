@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+)
+
+// sharedAddressSpace — адреса операторского NAT (RFC 6598), недоступные из внешней сети.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// webhookGuard запрещает доставку вебхуков на loopback, link-local, частные и другие внутренние адреса,
+// кроме указанных в WEBHOOK_ALLOW_LIST. Проверяется и адрес при создании правила, и адрес каждого
+// соединения при доставке, поэтому смена DNS-записи после создания правила не открывает доступ к внутренней сети.
+type webhookGuard struct {
+	hosts map[string]struct{}
+	nets  []*net.IPNet
+}
+
+// newWebhookGuard разбирает WEBHOOK_ALLOW_LIST: записи с IP или CIDR разрешают адреса, остальные — имена хостов.
+func newWebhookGuard(cfg *config.AlertsConf) *webhookGuard {
+	guard := &webhookGuard{hosts: make(map[string]struct{})}
+	if cfg == nil {
+		return guard
+	}
+
+	for _, entry := range cfg.WebhookAllowList {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			guard.nets = append(guard.nets, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			guard.nets = append(guard.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		if entry != common.Empty {
+			guard.hosts[entry] = struct{}{}
+		}
+	}
+	return guard
+}
+
+// validate проверяет схему и хост адреса вебхука и все адреса, в которые разрешается хост.
+func (g *webhookGuard) validate(ctx context.Context, rawURL string) (*url.URL, error) {
+	webhook, err := url.Parse(rawURL)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Hostname() == common.Empty {
+		return nil, fmt.Errorf("%w: scheme must be http or https and host must be set", common.ErrWebhookBlocked)
+	}
+
+	host := webhook.Hostname()
+	if g.allowedHost(host) {
+		return webhook, nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return webhook, g.checkIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot resolve %s", common.ErrWebhookBlocked, host)
+	}
+	for _, addr := range addrs {
+		if err := g.checkIP(addr.IP); err != nil {
+			return nil, err
+		}
+	}
+	return webhook, nil
+}
+
+// dialContext открывает соединение, проверяя фактический адрес после разрешения имени.
+func (g *webhookGuard) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: common.WebhookTimeout}
+	if host, _, err := net.SplitHostPort(address); err != nil || !g.allowedHost(host) {
+		dialer.Control = g.control
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// control проверяет адрес соединения перед подключением.
+func (g *webhookGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", common.ErrWebhookBlocked, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", common.ErrWebhookBlocked, address)
+	}
+	return g.checkIP(ip)
+}
+
+// allowedHost сообщает, указан ли хост в WEBHOOK_ALLOW_LIST по имени.
+func (g *webhookGuard) allowedHost(host string) bool {
+	_, ok := g.hosts[strings.ToLower(host)]
+	return ok
+}
+
+// checkIP возвращает ErrWebhookBlocked для внутреннего адреса, не разрешённого в WEBHOOK_ALLOW_LIST.
+func (g *webhookGuard) checkIP(ip net.IP) error {
+	for _, network := range g.nets {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", common.ErrWebhookBlocked, ip)
+	}
+	return nil
+}
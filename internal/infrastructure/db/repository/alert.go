@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type AlertRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAlertRepository(db *pgxpool.Pool) *AlertRepository {
+	return &AlertRepository{
+		db: db,
+	}
+}
+
+func (r *AlertRepository) CreateAlert(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	created, err := scanAlert(r.db.QueryRow(dbCtx, queryCreateAlert, strings.ToLower(rule.Coin), rule.Currency,
+		rule.Condition, rule.Threshold, rule.Window, rule.WebhookURL, rule.Secret))
+	if err != nil {
+		zap.L().Error("Error creating alert rule", zap.Error(err), zap.String("name:", rule.Coin))
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *AlertRepository) GetAlert(ctx context.Context, id int64) (*models.AlertRule, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rule, err := scanAlert(r.db.QueryRow(dbCtx, queryGetAlert, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrAlertNotFound
+		}
+		zap.L().Error("Error getting alert rule", zap.Error(err), zap.Int64("id:", id))
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *AlertRepository) ListAlerts(ctx context.Context) ([]*models.AlertRule, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryListAlerts)
+	if err != nil {
+		zap.L().Error("Error listing alert rules", zap.Error(err))
+		return nil, err
+	}
+	return collectAlerts(rows)
+}
+
+// ActiveAlerts возвращает включённые правила для указанных монет.
+func (r *AlertRepository) ActiveAlerts(ctx context.Context, coins []string) ([]*models.AlertRule, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryActiveAlerts, coins)
+	if err != nil {
+		zap.L().Error("Error listing active alert rules", zap.Error(err))
+		return nil, err
+	}
+	return collectAlerts(rows)
+}
+
+// DeleteAlert удаляет правило вместе с журналом его доставок.
+func (r *AlertRepository) DeleteAlert(ctx context.Context, id int64) error {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.Exec(dbCtx, queryDeleteAlert, id)
+	if err != nil {
+		zap.L().Error("Error deleting alert rule", zap.Error(err), zap.Int64("id:", id))
+		return err
+	}
+	if result.RowsAffected() == common.Zero {
+		return common.ErrAlertNotFound
+	}
+	return nil
+}
+
+// SaveEvaluation сохраняет результат проверки правил в одной транзакции: сработавшие правила
+// помечаются triggered и для каждого создаётся оповещение в журнале доставки, а правила
+// из Rearmed снова становятся активными.
+func (r *AlertRepository) SaveEvaluation(ctx context.Context, evaluation *models.AlertEvaluation) error {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, event := range evaluation.Fired {
+		batch.Queue(queryFireAlert, event.AlertID, event.Timestamp, event)
+	}
+	for _, id := range evaluation.Rearmed {
+		batch.Queue(queryRearmAlert, id)
+	}
+	if batch.Len() == common.Zero {
+		return nil
+	}
+
+	tx, err := r.db.Begin(dbCtx)
+	if err != nil {
+		zap.L().Error("begin transaction error", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback(dbCtx)
+
+	if err := tx.SendBatch(dbCtx, batch).Close(); err != nil {
+		zap.L().Error("save alert evaluation error", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+		zap.L().Error("commit transaction error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *AlertRepository) ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*models.AlertDelivery, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryListDeliveries, ruleID, limit)
+	if err != nil {
+		zap.L().Error("Error listing alert deliveries", zap.Error(err), zap.Int64("id:", ruleID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.AlertDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, false)
+		if err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating alert deliveries", zap.Error(err))
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDeliveries берёт в работу до limit оповещений, срок отправки которых наступил,
+// вместе с адресом и секретом вебхука их правила.
+func (r *AlertRepository) ClaimDeliveries(ctx context.Context, limit int) ([]*models.AlertDelivery, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryClaimDeliveries, limit, common.AlertDeliveryLease.Seconds())
+	if err != nil {
+		zap.L().Error("Error claiming alert deliveries", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.AlertDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, true)
+		if err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating alert deliveries", zap.Error(err))
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordDelivery сохраняет результат попытки отправки.
+//
+// Параметры:
+//   - id: идентификатор оповещения
+//   - status: новый статус (pending — будет повторено через retryIn)
+//   - code: HTTP-код ответа вебхука (0, если ответа не было)
+//   - message: текст ошибки (пустой при успехе)
+//   - retryIn: задержка до следующей попытки
+func (r *AlertRepository) RecordDelivery(ctx context.Context, id int64, status string, code int, message string, retryIn time.Duration) error {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	statusCode := pgtype.Int4{Int32: int32(code), Valid: code != common.Zero}
+	if _, err := r.db.Exec(dbCtx, queryRecordDelivery, id, status, statusCode, message, retryIn.Seconds()); err != nil {
+		zap.L().Error("Error recording alert delivery", zap.Error(err), zap.Int64("id:", id))
+		return err
+	}
+	return nil
+}
+
+// collectAlerts читает все строки alert_rules и закрывает rows.
+func collectAlerts(rows pgx.Rows) ([]*models.AlertRule, error) {
+	defer rows.Close()
+
+	rules := make([]*models.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlert(rows)
+		if err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating alert rules", zap.Error(err))
+		return nil, err
+	}
+	return rules, nil
+}
+
+// scanAlert читает строку alert_rules в порядке alertColumns.
+func scanAlert(row pgx.Row) (*models.AlertRule, error) {
+	rule := new(models.AlertRule)
+	var threshold pgtype.Numeric
+	err := row.Scan(&rule.ID, &rule.Coin, &rule.Currency, &rule.Condition, &threshold, &rule.Window,
+		&rule.WebhookURL, &rule.Secret, &rule.Enabled, &rule.Triggered, &rule.LastTriggeredAt, &rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if value := numericToDecimal(threshold); value != nil {
+		rule.Threshold = *value
+	}
+	return rule, nil
+}
+
+// scanDelivery читает строку alert_deliveries в порядке deliveryColumns;
+// при withWebhook дополнительно читаются адрес и секрет вебхука правила.
+func scanDelivery(row pgx.Row, withWebhook bool) (*models.AlertDelivery, error) {
+	delivery := new(models.AlertDelivery)
+	dest := []any{&delivery.ID, &delivery.RuleID, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&delivery.LastStatusCode, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.DeliveredAt}
+	if withWebhook {
+		dest = append(dest, &delivery.WebhookURL, &delivery.Secret)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type BackfillRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBackfillRepository(db *pgxpool.Pool) *BackfillRepository {
+	return &BackfillRepository{
+		db: db,
+	}
+}
+
+// CreateBackfill создаёт задачу дозагрузки истории в статусе pending.
+func (r *BackfillRepository) CreateBackfill(ctx context.Context, job *models.BackfillJob) (*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	created, err := scanBackfill(r.db.QueryRow(dbCtx, queryCreateBackfill, strings.ToLower(job.Coin), job.Currency, job.Provider, job.From, job.To))
+	if err != nil {
+		zap.L().Error("Error creating backfill job", zap.Error(err), zap.String("name:", job.Coin))
+		return nil, err
+	}
+	return created, nil
+}
+
+// FindActiveBackfill возвращает незавершённую задачу с теми же монетой, валютой и периодом.
+// Если такой задачи нет, возвращается ErrBackfillNotFound.
+func (r *BackfillRepository) FindActiveBackfill(ctx context.Context, coin, currency string, from, to int64) (*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	job, err := scanBackfill(r.db.QueryRow(dbCtx, queryFindActiveBackfill, strings.ToLower(coin), currency, from, to))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrBackfillNotFound
+		}
+		zap.L().Error("Error finding backfill job", zap.Error(err), zap.String("name:", coin))
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BackfillRepository) GetBackfill(ctx context.Context, id int64) (*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	job, err := scanBackfill(r.db.QueryRow(dbCtx, queryGetBackfill, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrBackfillNotFound
+		}
+		zap.L().Error("Error getting backfill job", zap.Error(err), zap.Int64("id:", id))
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *BackfillRepository) ListBackfills(ctx context.Context, limit int) ([]*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryListBackfills, limit)
+	if err != nil {
+		zap.L().Error("Error listing backfill jobs", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.BackfillJob, 0)
+	for rows.Next() {
+		job, err := scanBackfill(rows)
+		if err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating backfill jobs", zap.Error(err))
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ClaimBackfill переводит в статус running следующую ожидающую или прерванную задачу.
+// Если задач нет, возвращается ErrBackfillNotFound.
+func (r *BackfillRepository) ClaimBackfill(ctx context.Context) (*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	job, err := scanBackfill(r.db.QueryRow(dbCtx, queryClaimBackfill, common.BackfillLease.Seconds()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrBackfillNotFound
+		}
+		zap.L().Error("Error claiming backfill job", zap.Error(err))
+		return nil, err
+	}
+	return job, nil
+}
+
+// ClaimBackfillByID переводит в статус running указанную задачу.
+// Если задача завершена или выполняется другим процессом, возвращается ErrBackfillNotClaimed.
+func (r *BackfillRepository) ClaimBackfillByID(ctx context.Context, id int64) (*models.BackfillJob, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	job, err := scanBackfill(r.db.QueryRow(dbCtx, queryClaimBackfillByID, id, common.BackfillLease.Seconds()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrBackfillNotClaimed
+		}
+		zap.L().Error("Error claiming backfill job", zap.Error(err), zap.Int64("id:", id))
+		return nil, err
+	}
+	return job, nil
+}
+
+// SaveBackfillChunk сохраняет точки истории и сдвигает курсор задачи в одной транзакции,
+// поэтому после прерывания задача продолжается ровно с первой несохранённой части.
+// Точки, уже присутствующие в currency_prices (symbol, currency, timestamp), пропускаются.
+//
+// Возвращает число вставленных строк.
+func (r *BackfillRepository) SaveBackfillChunk(ctx context.Context, job *models.BackfillJob, points []*models.PricePoint, cursor int64) (int64, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	batch := backfillBatch(job, points)
+
+	tx, err := r.db.Begin(dbCtx)
+	if err != nil {
+		zap.L().Error("begin transaction error", zap.Error(err))
+		return common.Zero, err
+	}
+	defer tx.Rollback(dbCtx)
+
+	var inserted int64
+	results := tx.SendBatch(dbCtx, batch)
+	for range points {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			zap.L().Error("insert backfill points error", zap.Error(err), zap.Int64("id:", job.ID))
+			return common.Zero, err
+		}
+		inserted += tag.RowsAffected()
+	}
+	if err := results.Close(); err != nil {
+		zap.L().Error("insert backfill points error", zap.Error(err), zap.Int64("id:", job.ID))
+		return common.Zero, err
+	}
+
+	if _, err := tx.Exec(dbCtx, queryAdvanceBackfill, job.ID, cursor, len(points), inserted); err != nil {
+		zap.L().Error("advance backfill cursor error", zap.Error(err), zap.Int64("id:", job.ID))
+		return common.Zero, err
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+		zap.L().Error("commit transaction error", zap.Error(err))
+		return common.Zero, err
+	}
+	return inserted, nil
+}
+
+// backfillBatch формирует вставку точек истории задачи. История источника содержит только цену,
+// поэтому рыночные показатели сохраняются пустыми.
+func backfillBatch(job *models.BackfillJob, points []*models.PricePoint) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, point := range points {
+		price, precision := common.ScalePrice(point.Price)
+		batch.Queue(queryUpdatePriceCoin, job.Coin, point.Timestamp, price, precision, job.Currency, nil, nil, nil)
+	}
+	return batch
+}
+
+// FinishBackfill устанавливает итоговый статус задачи и текст ошибки.
+func (r *BackfillRepository) FinishBackfill(ctx context.Context, id int64, status, message string) error {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	if _, err := r.db.Exec(dbCtx, queryFinishBackfill, id, status, message); err != nil {
+		zap.L().Error("Error finishing backfill job", zap.Error(err), zap.Int64("id:", id))
+		return err
+	}
+	return nil
+}
+
+// scanBackfill читает строку backfill_jobs в порядке backfillColumns.
+func scanBackfill(row pgx.Row) (*models.BackfillJob, error) {
+	job := new(models.BackfillJob)
+	err := row.Scan(&job.ID, &job.Coin, &job.Currency, &job.Provider, &job.From, &job.To, &job.Cursor,
+		&job.Status, &job.Fetched, &job.Inserted, &job.Error, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"regexp"
+	"strconv"
+	"testYTask/internal/domain/models"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// placeholders возвращает наибольший номер параметра $N в запросе.
+func placeholders(sql string) int {
+	var count int
+	for _, match := range placeholder.FindAllStringSubmatch(sql, -1) {
+		n, _ := strconv.Atoi(match[1])
+		count = max(count, n)
+	}
+	return count
+}
+
+// checkBatch проверяет, что каждому запросу пакета передано столько аргументов, сколько в нём параметров.
+func checkBatch(t *testing.T, batch *pgx.Batch, want int) {
+	t.Helper()
+	if batch.Len() != want {
+		t.Fatalf("got %d queued queries, want %d", batch.Len(), want)
+	}
+	for i, query := range batch.QueuedQueries {
+		if got, params := len(query.Arguments), placeholders(query.SQL); got != params {
+			t.Errorf("query %d: %d arguments for %d placeholders: %s", i, got, params, query.SQL)
+		}
+	}
+}
+
+func TestBackfillBatch(t *testing.T) {
+	job := &models.BackfillJob{ID: 1, Coin: "bitcoin", Currency: "USD"}
+	points := []*models.PricePoint{
+		{Timestamp: 1754600400, Price: decimal.RequireFromString("117150.25")},
+		{Timestamp: 1754600700, Price: decimal.RequireFromString("0.000000001")},
+	}
+	checkBatch(t, backfillBatch(job, points), len(points))
+}
+
+func TestCoinUpdateBatch(t *testing.T) {
+	volume := models.NewDecimal(decimal.RequireFromString("1000"))
+	updates := []*models.CoinUpdate{
+		{
+			Coin:       "bitcoin",
+			Currency:   "USD",
+			Price:      decimal.RequireFromString("117150.25"),
+			MarketData: models.MarketData{Volume24h: &volume},
+			Timestamp:  1754600400,
+			Quotes: []*models.ProviderQuote{
+				{Provider: "coingecko", Currency: "USD", Price: decimal.RequireFromString("117150")},
+				{Provider: "binance", Currency: "USD", Price: decimal.RequireFromString("117150.5")},
+			},
+		},
+		{Coin: "ethereum", Currency: "EUR", Price: decimal.RequireFromString("3200"), Timestamp: 1754600400},
+	}
+	checkBatch(t, coinUpdateBatch(updates), 4)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type GapRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewGapRepository(db *pgxpool.Pool) *GapRepository {
+	return &GapRepository{
+		db: db,
+	}
+}
+
+// DetectGaps ищет пропуски во всех отслеживаемых парах начиная с since и сохраняет новые в price_gaps.
+// Пропуск от последней цены пары до текущего момента тоже сохраняется и продлевается при следующих запусках.
+//
+// Возвращает только пропуски, найденные впервые.
+func (r *GapRepository) DetectGaps(ctx context.Context, since, threshold int64) ([]*models.PriceGap, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryDetectGaps, since, threshold)
+	if err != nil {
+		zap.L().Error("Error detecting price gaps", zap.Error(err))
+		return nil, err
+	}
+	return collectGaps(rows)
+}
+
+func (r *GapRepository) ListGaps(ctx context.Context, req *models.GapRequest) ([]*models.PriceGap, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryListGaps, strings.ToLower(req.Coin), req.Currency, req.Status, req.Limit)
+	if err != nil {
+		zap.L().Error("Error listing price gaps", zap.Error(err))
+		return nil, err
+	}
+	return collectGaps(rows)
+}
+
+func (r *GapRepository) GetGap(ctx context.Context, id int64) (*models.PriceGap, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	gap, err := scanGap(r.db.QueryRow(dbCtx, queryGetGap, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrGapNotFound
+		}
+		zap.L().Error("Error getting price gap", zap.Error(err), zap.Int64("id:", id))
+		return nil, err
+	}
+	return gap, nil
+}
+
+// AttachBackfill привязывает задачу дозагрузки к пропуску и переводит его в статус repairing.
+// Если пропуск уже заполнен или заполняется, возвращается ErrGapNotRepairable.
+func (r *GapRepository) AttachBackfill(ctx context.Context, id, backfillID int64) (*models.PriceGap, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	gap, err := scanGap(r.db.QueryRow(dbCtx, queryAttachGapBackfill, id, backfillID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrGapNotRepairable
+		}
+		zap.L().Error("Error attaching backfill to price gap", zap.Error(err), zap.Int64("id:", id))
+		return nil, err
+	}
+	return gap, nil
+}
+
+// SyncGapStatuses переводит пропуски в статус repaired, unrepairable или failed по итогу их задач дозагрузки.
+// Пропуск считается заполненным, только если в его интервале не осталось промежутка между ценами длиннее maxStep.
+//
+// Возвращает число обновлённых пропусков.
+func (r *GapRepository) SyncGapStatuses(ctx context.Context, maxStep int64) (int64, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	result, err := r.db.Exec(dbCtx, querySyncGapStatuses, maxStep)
+	if err != nil {
+		zap.L().Error("Error syncing price gap statuses", zap.Error(err))
+		return common.Zero, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// collectGaps читает все строки price_gaps и закрывает rows.
+func collectGaps(rows pgx.Rows) ([]*models.PriceGap, error) {
+	defer rows.Close()
+
+	gaps := make([]*models.PriceGap, 0)
+	for rows.Next() {
+		gap, err := scanGap(rows)
+		if err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating price gaps", zap.Error(err))
+		return nil, err
+	}
+	return gaps, nil
+}
+
+// scanGap читает строку price_gaps в порядке gapColumns.
+func scanGap(row pgx.Row) (*models.PriceGap, error) {
+	gap := new(models.PriceGap)
+	err := row.Scan(&gap.ID, &gap.Coin, &gap.Currency, &gap.Start, &gap.End, &gap.Duration,
+		&gap.Status, &gap.BackfillID, &gap.DetectedAt, &gap.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return gap, nil
+}
@@ -2,11 +2,10 @@ package repository
 
 import (
 	"context"
-	"math"
 	"testYTask/internal/common"
 	"testYTask/internal/domain/models"
-	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
@@ -21,23 +20,81 @@ func NewJobRepository(db *pgxpool.Pool) *JobRepository {
 	}
 }
 
-func (r *JobRepository) CoinDataUpdate(ctx context.Context, coin string, data *models.CoinPrice, precision int, currency string) error {
+// CoinDataUpdate сохраняет согласованные цены монет с рыночными показателями и котировки источников,
+// из которых они получены. Все строки отправляются одним pgx.Batch внутри транзакции.
+// Цена, метка времени которой для пары уже сохранена, пропускается вместе с котировками; пропуски логируются.
+// Каждая цена хранится как целое число с собственной точностью (см. common.ScalePrice).
+//
+// Возвращает цены, которые были фактически вставлены.
+func (r *JobRepository) CoinDataUpdate(ctx context.Context, updates []*models.CoinUpdate) ([]*models.CoinUpdate, error) {
+	if len(updates) == common.Zero {
+		return nil, nil
+	}
+
 	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
 	defer cancel()
 
-	timestamp := time.Now().Unix()
+	batch := coinUpdateBatch(updates)
+
+	tx, err := r.db.Begin(dbCtx)
+	if err != nil {
+		zap.L().Error("begin transaction error", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback(dbCtx)
+
+	inserted := make([]*models.CoinUpdate, 0, len(updates))
+	results := tx.SendBatch(dbCtx, batch)
+	for _, update := range updates {
+		tag, err := results.Exec()
+		if err != nil {
+			zap.L().Error("update coin data error", zap.Error(err), zap.String("coin:", update.Coin), zap.String("currency:", update.Currency))
+			results.Close()
+			return nil, err
+		}
+		if tag.RowsAffected() == common.Zero {
+			zap.L().Info("Price sample already stored, skipping", zap.String("coin:", update.Coin), zap.String("currency:", update.Currency), zap.Int64("timestamp:", update.Timestamp))
+		} else {
+			inserted = append(inserted, update)
+		}
+
+		for range update.Quotes {
+			if _, err := results.Exec(); err != nil {
+				zap.L().Error("insert provider quote error", zap.Error(err), zap.String("coin:", update.Coin), zap.String("currency:", update.Currency))
+				results.Close()
+				return nil, err
+			}
+		}
+	}
+	if err := results.Close(); err != nil {
+		zap.L().Error("update coin data error", zap.Error(err), zap.Int("coins:", len(updates)))
+		return nil, err
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+		zap.L().Error("commit transaction error", zap.Error(err))
+		return nil, err
+	}
+	return inserted, nil
+}
 
-	multiplier := math.Pow10(precision)
-	intPrice := int64(data.USD * multiplier)
+// coinUpdateBatch формирует вставку цен и котировок: за каждой ценой следуют котировки, из которых она получена.
+func coinUpdateBatch(updates []*models.CoinUpdate) *pgx.Batch {
+	batch := &pgx.Batch{}
+	for _, update := range updates {
+		intPrice, precision := common.ScalePrice(update.Price)
+		batch.Queue(queryUpdatePriceCoin, update.Coin, update.Timestamp, intPrice, precision, update.Currency,
+			update.MarketCap, update.Volume24h, update.Change24h)
 
-	if _, err := r.db.Exec(dbCtx, queryUpdatePriceCoin, coin, timestamp, intPrice, precision, currency); err != nil {
-		zap.L().Error("update coin data error", zap.Error(err), zap.String("name:", coin))
-		return err
+		for _, quote := range update.Quotes {
+			quotePrice, quotePrecision := common.ScalePrice(quote.Price)
+			batch.Queue(queryInsertQuote, update.Coin, quote.Provider, update.Timestamp, quotePrice, quotePrecision, quote.Currency, quote.Outlier)
+		}
 	}
-	return nil
+	return batch
 }
 
-func (r *JobRepository) ListOfCurrentCoins(ctx context.Context) ([]string, error) {
+func (r *JobRepository) ListOfCurrentCoins(ctx context.Context) ([]*models.WatchedCoin, error) {
 	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
 	defer cancel()
 
@@ -48,10 +105,10 @@ func (r *JobRepository) ListOfCurrentCoins(ctx context.Context) ([]string, error
 	}
 	defer rows.Close()
 
-	var coins []string
+	var coins []*models.WatchedCoin
 	for rows.Next() {
-		var c string
-		if err := rows.Scan(&c); err != nil {
+		c := new(models.WatchedCoin)
+		if err := rows.Scan(&c.Symbol, &c.Currencies); err != nil {
 			zap.L().Error("Scan error", zap.Error(err))
 			return nil, err
 		}
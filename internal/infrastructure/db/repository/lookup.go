@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+)
+
+// resolvePrice выбирает или интерполирует цену из сэмплов, найденных для режима запроса,
+// и заполняет метки времени использованных сэмплов и расстояние до них.
+func resolvePrice(req *models.PriceRequest, samples []*models.PriceResponse) (*models.PriceResponse, error) {
+	mode := req.Mode
+	if mode == common.Empty {
+		mode = common.ModeNearest
+	}
+
+	var response *models.PriceResponse
+
+	switch mode {
+	case common.ModeLinear:
+		var lower, upper *models.PriceResponse
+		for _, sample := range samples {
+			if sample.Timestamp <= req.Timestamp {
+				lower = sample
+			}
+			if sample.Timestamp >= req.Timestamp {
+				upper = sample
+			}
+		}
+		if lower == nil || upper == nil {
+			return nil, common.ErrPriceNotFound
+		}
+
+		// Рыночные показатели не интерполируются: берутся из ближайшего сэмпла, при равенстве — из более раннего.
+		nearest := lower
+		if distance(upper.Timestamp, req.Timestamp) < distance(lower.Timestamp, req.Timestamp) {
+			nearest = upper
+		}
+
+		response = &models.PriceResponse{
+			Coin:       lower.Coin,
+			Price:      models.NewDecimal(common.InterpolatePrice(lower.Timestamp, lower.Price.Decimal, upper.Timestamp, upper.Price.Decimal, req.Timestamp)),
+			Currency:   lower.Currency,
+			Timestamp:  req.Timestamp,
+			MarketData: nearest.MarketData,
+		}
+		if lower.Timestamp == upper.Timestamp {
+			response.SampleTimestamps = []int64{lower.Timestamp}
+		} else {
+			response.SampleTimestamps = []int64{lower.Timestamp, upper.Timestamp}
+		}
+	default:
+		// Для nearest из двух соседей выбирается ближайший, при равенстве — более ранний.
+		response = samples[0]
+		for _, sample := range samples[1:] {
+			if distance(sample.Timestamp, req.Timestamp) < distance(response.Timestamp, req.Timestamp) {
+				response = sample
+			}
+		}
+		response.SampleTimestamps = []int64{response.Timestamp}
+	}
+
+	var maxDistance int64
+	for _, timestamp := range response.SampleTimestamps {
+		maxDistance = max(maxDistance, distance(timestamp, req.Timestamp))
+	}
+	if req.MaxDistance > common.Zero && maxDistance > req.MaxDistance {
+		return nil, common.ErrPriceTooFar
+	}
+
+	response.Mode = mode
+	response.Distance = &maxDistance
+	return response, nil
+}
+
+// distance возвращает расстояние между двумя метками времени (сек).
+func distance(a, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -3,13 +3,16 @@ package repository
 import (
 	"context"
 	"errors"
-	"math"
+	"fmt"
 	"strings"
 	"testYTask/internal/common"
 	"testYTask/internal/domain/models"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -23,14 +26,20 @@ func NewMajorRepository(db *pgxpool.Pool) *MajorRepository {
 	}
 }
 
+// AddingNewCoin добавляет монету в отслеживание или объединяет её валюты с уже отслеживаемыми.
+// Возвращает ErrTooManyCurrencies, если после объединения валют станет больше MaxCurrenciesPerCoin.
 func (m *MajorRepository) AddingNewCoin(ctx context.Context, coin *models.Coin) error {
 	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
 	defer cancel()
 
-	if _, err := m.db.Exec(dbCtx, queryAddingNewCoin, strings.ToLower(coin.NameCoin)); err != nil {
+	result, err := m.db.Exec(dbCtx, queryAddingNewCoin, strings.ToLower(coin.NameCoin), coin.Currencies, common.MaxCurrenciesPerCoin)
+	if err != nil {
 		zap.L().Error("Error adding new coin", zap.Error(err))
 		return err
 	}
+	if result.RowsAffected() == common.Zero {
+		return common.ErrTooManyCurrencies
+	}
 	return nil
 }
 
@@ -51,29 +60,490 @@ func (m *MajorRepository) DeleteCoin(ctx context.Context, coin *models.Coin) err
 	return nil
 }
 
-func (m *MajorRepository) GetPrice(ctx context.Context, req *models.PriceRequest) (*models.PriceResponse, error) {
+// RemoveCurrencies прекращает отслеживание монеты в указанных валютах. Если валют не осталось,
+// монета удаляется из отслеживания целиком. Сохранённые цены не удаляются.
+// Возвращает ErrCoinNotFound, если монета не отслеживается ни в одной из указанных валют.
+func (m *MajorRepository) RemoveCurrencies(ctx context.Context, coin *models.Coin) error {
 	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
 	defer cancel()
 
-	var DbResponse models.DbResponse
-
-	row := m.db.QueryRow(dbCtx, queryGetPriceForCoin, strings.ToLower(req.Coin), req.Timestamp)
-	err := row.Scan(&DbResponse.Coin, &DbResponse.Price, &DbResponse.Precision, &DbResponse.Currency, &DbResponse.Timestamp)
+	tx, err := m.db.Begin(dbCtx)
 	if err != nil {
+		zap.L().Error("begin transaction error", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback(dbCtx)
+
+	var remaining int
+	if err := tx.QueryRow(dbCtx, queryRemoveCurrencies, strings.ToLower(coin.NameCoin), coin.Currencies).Scan(&remaining); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, common.ErrPriceNotFound
+			return common.ErrCoinNotFound
+		}
+		zap.L().Error("Error removing coin currencies", zap.Error(err))
+		return err
+	}
+	if remaining == common.Zero {
+		if _, err := tx.Exec(dbCtx, queryDeleteCoin, strings.ToLower(coin.NameCoin)); err != nil {
+			zap.L().Error("Error deleting coin", zap.Error(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+		zap.L().Error("commit transaction error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// WatchedCoins возвращает отслеживаемые монеты и валюты, в которых сохраняются их цены.
+func (m *MajorRepository) WatchedCoins(ctx context.Context) ([]*models.WatchedCoin, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := m.db.Query(dbCtx, queryListRequest)
+	if err != nil {
+		zap.L().Error("Error listing watched coins", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coins []*models.WatchedCoin
+	for rows.Next() {
+		coin := new(models.WatchedCoin)
+		if err := rows.Scan(&coin.Symbol, &coin.Currencies); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		coins = append(coins, coin)
+	}
+	return coins, rows.Err()
+}
+
+// ListWatchedCoins возвращает страницу отслеживаемых монет с последней ценой, числом цен и устарелостью
+// по каждой валюте котировки. Фильтр stale, сортировка и постраничный вывод применяются к монетам целиком
+// на стороне базы данных.
+func (m *MajorRepository) ListWatchedCoins(ctx context.Context, req *models.WatchedCoinRequest) (*models.WatchedCoinList, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	now := time.Now().Unix()
+	rows, err := m.db.Query(dbCtx, fmt.Sprintf(queryListWatchedCoins, watchedOrder(req)),
+		strings.ToLower(req.Coin), req.Currency, now, req.StaleAfter, req.Stale, req.Limit, req.Offset)
+	if err != nil {
+		zap.L().Error("Error listing watched coins", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := &models.WatchedCoinList{
+		Items:  make([]*models.WatchedCoinInfo, 0),
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+	for rows.Next() {
+		var (
+			total     int
+			symbol    *string
+			currency  *string
+			addedAt   *time.Time
+			price     *int64
+			precision *int
+			timestamp *int64
+			samples   *int64
+		)
+		if err := rows.Scan(&total, &symbol, &addedAt, &currency, &price, &precision, &timestamp, &samples); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		list.Total = total
+		if symbol == nil || currency == nil {
+			continue
 		}
 
+		items := list.Items
+		if len(items) == common.Zero || items[len(items)-1].Coin != *symbol {
+			list.Items = append(list.Items, &models.WatchedCoinInfo{
+				Coin:    *symbol,
+				AddedAt: addedAt,
+				Quotes:  make([]*models.WatchedQuote, 0, 1),
+			})
+		}
+		quote := &models.WatchedQuote{
+			Currency:      *currency,
+			LastTimestamp: timestamp,
+		}
+		if samples != nil {
+			quote.Samples = *samples
+		}
+		if price != nil {
+			lastPrice := common.UnscalePrice(*price, *precision)
+			quote.LastPrice = models.NewDecimalPtr(&lastPrice)
+		}
+		addWatchedQuote(list.Items[len(list.Items)-1], quote, now, req.StaleAfter)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error listing watched coins", zap.Error(err))
+		return nil, err
+	}
+	return list, nil
+}
+
+// MarketTickers возвращает последнюю цену каждой отслеживаемой пары в валюте currency (пустая строка — во всех валютах)
+// и её изменение за каждое из окон windows. Все пары и окна выбираются одним запросом.
+// Пары без сохранённых цен не возвращаются.
+func (m *MajorRepository) MarketTickers(ctx context.Context, currency string, windows []*models.MarketWindow) ([]*models.MarketTicker, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	seconds := make([]int64, len(windows))
+	for i, window := range windows {
+		seconds[i] = window.Seconds
+	}
+
+	rows, err := m.db.Query(dbCtx, queryMarketTickers, currency, seconds)
+	if err != nil {
+		zap.L().Error("Error getting market tickers", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	tickers := make([]*models.MarketTicker, 0)
+	for rows.Next() {
+		var (
+			DbResponse   models.DbResponse
+			market       marketNumerics
+			idx          int
+			refPrice     *int64
+			refPrecision *int
+			refTimestamp *int64
+		)
+		if err := rows.Scan(&DbResponse.Coin, &DbResponse.Currency,
+			&DbResponse.Price, &DbResponse.Precision, &DbResponse.Timestamp, &market.marketCap, &market.volume, &market.change,
+			&idx, &refPrice, &refPrecision, &refTimestamp); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+
+		last := len(tickers) - 1
+		if last < common.Zero || tickers[last].Coin != DbResponse.Coin || tickers[last].Currency != DbResponse.Currency {
+			tickers = append(tickers, &models.MarketTicker{
+				Coin:       DbResponse.Coin,
+				Currency:   DbResponse.Currency,
+				Price:      models.NewDecimal(common.UnscalePrice(DbResponse.Price, DbResponse.Precision)),
+				Timestamp:  DbResponse.Timestamp,
+				MarketData: market.data(),
+				Changes:    make(map[string]*models.PriceChange, len(windows)),
+			})
+			last++
+		}
+		ticker := tickers[last]
+		ticker.Changes[windows[idx-1].Name] = priceChange(ticker.Price.Decimal, refPrice, refPrecision, refTimestamp)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating market tickers", zap.Error(err))
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// GetPrice возвращает цену монеты на момент req.Timestamp в режиме req.Mode (по умолчанию nearest).
+//
+// Возвращает ErrPriceNotFound, если подходящих сэмплов нет (для linear — нет сэмпла с одной из сторон),
+// и ErrPriceTooFar, если расстояние до сэмплов больше req.MaxDistance.
+func (m *MajorRepository) GetPrice(ctx context.Context, req *models.PriceRequest) (*models.PriceResponse, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	query := queryGetPriceAround
+	switch req.Mode {
+	case common.ModeBefore:
+		query = queryGetPriceBefore
+	case common.ModeAfter:
+		query = queryGetPriceAfter
+	}
+
+	rows, err := m.db.Query(dbCtx, query, strings.ToLower(req.Coin), req.Timestamp, req.Currency)
+	if err != nil {
+		zap.L().Error("Error getting price", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := make([]*models.PriceResponse, 0, 2)
+	for rows.Next() {
+		var (
+			DbResponse models.DbResponse
+			market     marketNumerics
+		)
+		if err := rows.Scan(&DbResponse.Coin, &DbResponse.Price, &DbResponse.Precision, &DbResponse.Currency, &DbResponse.Timestamp,
+			&market.marketCap, &market.volume, &market.change); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		samples = append(samples, &models.PriceResponse{
+			Coin:       DbResponse.Coin,
+			Price:      models.NewDecimal(common.UnscalePrice(DbResponse.Price, DbResponse.Precision)),
+			Currency:   DbResponse.Currency,
+			Timestamp:  DbResponse.Timestamp,
+			MarketData: market.data(),
+		})
+	}
+	if err := rows.Err(); err != nil {
 		zap.L().Error("Error getting price", zap.Error(err))
 		return nil, err
 	}
+	if len(samples) == common.Zero {
+		return nil, common.ErrPriceNotFound
+	}
+
+	return resolvePrice(req, samples)
+}
+
+// GetPrices возвращает цены для пакета запросов одним запросом к базе.
+//
+// Для каждого элемента выбираются соседние сэмплы, затем цена определяется так же, как в GetPrice.
+// Ошибки отдельных элементов (ErrPriceNotFound, ErrPriceTooFar) возвращаются в errs по индексу элемента
+// и не прерывают обработку пакета; ошибка err означает сбой всего запроса.
+func (m *MajorRepository) GetPrices(ctx context.Context, reqs []*models.PriceRequest) ([]*models.PriceResponse, []error, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	coins := make([]string, len(reqs))
+	timestamps := make([]int64, len(reqs))
+	currencies := make([]string, len(reqs))
+	for i, req := range reqs {
+		coins[i] = strings.ToLower(req.Coin)
+		timestamps[i] = req.Timestamp
+		currencies[i] = req.Currency
+	}
+
+	rows, err := m.db.Query(dbCtx, queryGetPricesAround, coins, timestamps, currencies)
+	if err != nil {
+		zap.L().Error("Error getting prices", zap.Error(err), zap.Int("items:", len(reqs)))
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]*models.PriceResponse, len(reqs))
+	errs := make([]error, len(reqs))
+	for rows.Next() {
+		var (
+			idx                             int
+			beforePrice, afterPrice         *int64
+			beforePrecision, afterPrecision *int
+			beforeTimestamp, afterTimestamp *int64
+			beforeMarket, afterMarket       marketNumerics
+		)
+		if err := rows.Scan(&idx,
+			&beforePrice, &beforePrecision, &beforeTimestamp, &beforeMarket.marketCap, &beforeMarket.volume, &beforeMarket.change,
+			&afterPrice, &afterPrecision, &afterTimestamp, &afterMarket.marketCap, &afterMarket.volume, &afterMarket.change); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, nil, err
+		}
+
+		i := idx - 1
+		req := reqs[i]
+
+		samples := make([]*models.PriceResponse, 0, 2)
+		if beforePrice != nil && req.Mode != common.ModeAfter {
+			samples = append(samples, &models.PriceResponse{
+				Coin:       coins[i],
+				Price:      models.NewDecimal(common.UnscalePrice(*beforePrice, *beforePrecision)),
+				Currency:   req.Currency,
+				Timestamp:  *beforeTimestamp,
+				MarketData: beforeMarket.data(),
+			})
+		}
+		if afterPrice != nil && req.Mode != common.ModeBefore {
+			samples = append(samples, &models.PriceResponse{
+				Coin:       coins[i],
+				Price:      models.NewDecimal(common.UnscalePrice(*afterPrice, *afterPrecision)),
+				Currency:   req.Currency,
+				Timestamp:  *afterTimestamp,
+				MarketData: afterMarket.data(),
+			})
+		}
+		if len(samples) == common.Zero {
+			errs[i] = common.ErrPriceNotFound
+			continue
+		}
+
+		prices[i], errs[i] = resolvePrice(req, samples)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating prices", zap.Error(err))
+		return nil, nil, err
+	}
+	return prices, errs, nil
+}
+
+func (m *MajorRepository) GetHistory(ctx context.Context, req *models.HistoryRequest) (*models.HistoryResponse, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	// Запрашиваем на одну строку больше лимита, чтобы понять, есть ли следующая страница.
+	rows, err := m.db.Query(dbCtx, queryGetHistoryForCoin, strings.ToLower(req.Coin), req.From, req.To, req.Cursor, req.Limit+1, req.Currency)
+	if err != nil {
+		zap.L().Error("Error getting price history", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.PriceResponse, 0, req.Limit)
+	for rows.Next() {
+		var (
+			DbResponse models.DbResponse
+			market     marketNumerics
+		)
+		if err := rows.Scan(&DbResponse.Coin, &DbResponse.Price, &DbResponse.Precision, &DbResponse.Currency, &DbResponse.Timestamp,
+			&market.marketCap, &market.volume, &market.change); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		items = append(items, &models.PriceResponse{
+			Coin:       DbResponse.Coin,
+			Price:      models.NewDecimal(common.UnscalePrice(DbResponse.Price, DbResponse.Precision)),
+			Currency:   DbResponse.Currency,
+			Timestamp:  DbResponse.Timestamp,
+			MarketData: market.data(),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating price history", zap.Error(err))
+		return nil, err
+	}
+
+	response := &models.HistoryResponse{
+		Coin:     strings.ToLower(req.Coin),
+		Currency: req.Currency,
+		From:     req.From,
+		To:       req.To,
+		Items:    items,
+	}
+	if len(items) > req.Limit {
+		response.Items = items[:req.Limit]
+		nextCursor := response.Items[req.Limit-1].Timestamp
+		response.NextCursor = &nextCursor
+	}
+	return response, nil
+}
+
+// GetAveragePrice возвращает TWAP и VWAP монеты за окно [req.From, req.To] и покрытие окна сэмплами.
+// Средние округляются до MaxPrecision знаков после запятой, доли покрытия — до CoveragePrecision.
+func (m *MajorRepository) GetAveragePrice(ctx context.Context, req *models.AveragePriceRequest) (*models.AveragePriceResponse, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	var (
+		twap, vwap, volume     pgtype.Numeric
+		covered, volumeCovered int64
+	)
+	response := &models.AveragePriceResponse{
+		Coin:     strings.ToLower(req.Coin),
+		Currency: req.Currency,
+		From:     req.From,
+		To:       req.To,
+		MaxGap:   req.MaxGap,
+	}
+	if err := m.db.QueryRow(dbCtx, queryGetAveragePrice, response.Coin, req.Currency, req.From, req.To, req.MaxGap).
+		Scan(&twap, &response.Samples, &covered, &vwap, &volume, &response.VolumeSamples, &volumeCovered); err != nil {
+		zap.L().Error("Error getting average price", zap.Error(err), zap.String("name:", req.Coin))
+		return nil, err
+	}
+
+	response.TWAP = roundPrice(numericToDecimal(twap))
+	response.VWAP = roundPrice(numericToDecimal(vwap))
+	if response.VWAP != nil {
+		response.Volume = roundPrice(numericToDecimal(volume))
+	}
+
+	length := decimal.NewFromInt(req.To - req.From)
+	response.CoveredSeconds = covered
+	response.Coverage = models.NewDecimal(decimal.NewFromInt(covered).DivRound(length, common.CoveragePrecision))
+	response.VolumeCoverage = models.NewDecimal(decimal.NewFromInt(volumeCovered).DivRound(length, common.CoveragePrecision))
+	return response, nil
+}
+
+// GetCloses возвращает цены закрытия непустых интервалов длиной step для каждой из монет coins
+// за период [from, to] одним запросом. Интервалы отсчитываются от from; точки упорядочены по времени,
+// Timestamp точки — начало интервала. Монеты без цен в результат не попадают.
+func (m *MajorRepository) GetCloses(ctx context.Context, coins []string, currency string, from, to, step int64) (map[string][]*models.PricePoint, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := m.db.Query(dbCtx, queryGetClosesForCoins, coins, from, to, step, currency)
+	if err != nil {
+		zap.L().Error("Error getting closes", zap.Error(err), zap.Int("coins:", len(coins)))
+		return nil, err
+	}
+	defer rows.Close()
 
-	price := DbResponse.Price / math.Pow10(DbResponse.Precision)
+	closes := make(map[string][]*models.PricePoint, len(coins))
+	for rows.Next() {
+		var (
+			symbol    string
+			bucket    int64
+			price     int64
+			precision int
+		)
+		if err := rows.Scan(&symbol, &bucket, &price, &precision); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		closes[symbol] = append(closes[symbol], &models.PricePoint{
+			Timestamp: bucket,
+			Price:     common.UnscalePrice(price, precision),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating closes", zap.Error(err))
+		return nil, err
+	}
+	return closes, nil
+}
+
+func (m *MajorRepository) GetCandles(ctx context.Context, req *models.CandleRequest) (*models.CandleResponse, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	// Выравниваем начало периода по границе интервала, чтобы свечи 1h/1d начинались с начала часа/суток (UTC).
+	start := req.From - req.From%req.Step
+
+	rows, err := m.db.Query(dbCtx, queryGetCandlesForCoin, strings.ToLower(req.Coin), start, req.To, req.Step, req.Currency)
+	if err != nil {
+		zap.L().Error("Error getting candles", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	candles := make([]*models.Candle, 0)
+	for rows.Next() {
+		var open, high, low, closePrice pgtype.Numeric
+
+		candle := new(models.Candle)
+		if err := rows.Scan(&candle.Timestamp, &open, &high, &low, &closePrice, &candle.Count); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, err
+		}
+		candle.Open = numericToDecimal(open)
+		candle.High = numericToDecimal(high)
+		candle.Low = numericToDecimal(low)
+		candle.Close = numericToDecimal(closePrice)
+		candle.Empty = candle.Count == common.Zero
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error iterating candles", zap.Error(err))
+		return nil, err
+	}
 
-	return &models.PriceResponse{
-		Coin:      DbResponse.Coin,
-		Price:     price,
-		Currency:  DbResponse.Currency,
-		Timestamp: DbResponse.Timestamp,
+	return &models.CandleResponse{
+		Coin:     strings.ToLower(req.Coin),
+		Currency: req.Currency,
+		Interval: req.Step,
+		From:     start,
+		To:       req.To,
+		Candles:  candles,
 	}, nil
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// priceChange рассчитывает изменение цены относительно опорного сэмпла в процентах
+// с точностью ChangePrecision знаков. Если опорного сэмпла нет или его цена равна нулю, Percent остаётся nil.
+func priceChange(price decimal.Decimal, refPrice *int64, refPrecision *int, refTimestamp *int64) *models.PriceChange {
+	change := new(models.PriceChange)
+	if refPrice == nil {
+		return change
+	}
+
+	reference := common.UnscalePrice(*refPrice, *refPrecision)
+	change.ReferencePrice = models.NewDecimalPtr(&reference)
+	change.ReferenceTimestamp = refTimestamp
+	if reference.IsZero() {
+		return change
+	}
+
+	percent := price.Sub(reference).Mul(hundred).DivRound(reference, common.ChangePrecision)
+	change.Percent = models.NewDecimalPtr(&percent)
+	return change
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// numericToDecimal переводит значение numeric из PostgreSQL в точное десятичное значение ответа.
+// Для NULL, NaN и бесконечностей возвращает nil.
+func numericToDecimal(value pgtype.Numeric) *models.Decimal {
+	if !value.Valid || value.NaN || value.InfinityModifier != pgtype.Finite || value.Int == nil {
+		return nil
+	}
+
+	result := models.NewDecimal(decimal.NewFromBigInt(value.Int, value.Exp))
+	return &result
+}
+
+// roundPrice округляет вычисленную в базе цену до MaxPrecision знаков после запятой; nil остаётся nil.
+func roundPrice(value *models.Decimal) *models.Decimal {
+	if value == nil {
+		return nil
+	}
+	rounded := models.NewDecimal(value.Round(common.MaxPrecision))
+	return &rounded
+}
+
+// marketNumerics — столбцы market_cap, volume_24h и change_24h одного сэмпла цены.
+type marketNumerics struct {
+	marketCap, volume, change pgtype.Numeric
+}
+
+// data переводит рыночные показатели сэмпла в models.MarketData; для NULL поля остаются nil.
+func (n *marketNumerics) data() models.MarketData {
+	return models.MarketData{
+		MarketCap: numericToDecimal(n.marketCap),
+		Volume24h: numericToDecimal(n.volume),
+		Change24h: numericToDecimal(n.change),
+	}
+}
@@ -1,25 +1,418 @@
 package repository
 
 const (
-	queryAddingNewCoin = `INSERT INTO public.watched_currencies (symbol) VALUES ($1) ON CONFLICT DO NOTHING;`
-	queryDeleteCoin    = `DELETE FROM public.watched_currencies WHERE symbol = $1;`
+	// Валюты объединяются с уже отслеживаемыми; если объединение длиннее $3, строка не обновляется.
+	queryAddingNewCoin = `INSERT INTO public.watched_currencies (symbol, currencies) VALUES ($1, $2)
+		ON CONFLICT (symbol) DO UPDATE
+		SET currencies = ARRAY(
+			SELECT DISTINCT c FROM unnest(public.watched_currencies.currencies || EXCLUDED.currencies) AS c ORDER BY c
+		)
+		WHERE (
+			SELECT count(DISTINCT c) FROM unnest(public.watched_currencies.currencies || EXCLUDED.currencies) AS c
+		) <= $3;`
+	queryDeleteCoin = `DELETE FROM public.watched_currencies WHERE symbol = $1;`
 
-	queryListRequest     = `SELECT symbol FROM public.watched_currencies;`
-	queryUpdatePriceCoin = `INSERT INTO public.currency_prices (symbol, "timestamp", price, "precision", currency) VALUES ($1, $2, $3, $4, $5);`
+	// Из отслеживаемых валют монеты удаляются валюты $2; строка обновляется, только если хотя бы одна из них отслеживается.
+	queryRemoveCurrencies = `UPDATE public.watched_currencies
+		SET currencies = ARRAY(SELECT c FROM unnest(currencies) AS c WHERE c <> ALL($2) ORDER BY c)
+		WHERE symbol = $1 AND currencies && $2
+		RETURNING cardinality(currencies);`
 
-	queryGetPriceForCoin = `(
-  		SELECT symbol, price, "precision", currency, "timestamp"
+	// Страница отслеживаемых монет: для каждой пары (symbol, currency) выбираются последняя цена и число цен
+	// из price_sample_counts, по парам монеты считаются сводные значения, затем к монетам применяются фильтр stale,
+	// сортировка %s (см. watchedOrder) и LIMIT $6 OFFSET $7. Возвращается строка на пару монеты из страницы
+	// и число монет после фильтрации; если страница пуста, возвращается одна строка только с этим числом.
+	// $1 — подстрока ID монеты, $2 — валюта котировки (пустые значения не фильтруют), $3 — текущее время,
+	// $4 — порог устарелости (сек), $5 — фильтр stale (NULL не фильтрует).
+	queryListWatchedCoins = `WITH quotes AS (
+			SELECT wc.symbol, wc.added_at, c.currency, last.price, last."precision", last."timestamp",
+				COALESCE(cnt.samples, 0) AS samples,
+				CASE WHEN last."timestamp" IS NOT NULL THEN GREATEST($3::int8 - last."timestamp", 0) END AS staleness
+			FROM public.watched_currencies wc
+			CROSS JOIN LATERAL unnest(wc.currencies) AS c(currency)
+			LEFT JOIN LATERAL (
+				SELECT p.price, p."precision", p."timestamp"
+				FROM public.currency_prices p
+				WHERE p.symbol = wc.symbol AND p.currency = c.currency
+				ORDER BY p."timestamp" DESC
+				LIMIT 1
+			) last ON true
+			LEFT JOIN public.price_sample_counts cnt ON cnt.symbol = wc.symbol AND cnt.currency = c.currency
+			WHERE ($1 = '' OR strpos(wc.symbol, $1) > 0) AND ($2 = '' OR c.currency = $2)
+		), coins AS (
+			SELECT symbol, added_at, max("timestamp") AS last_timestamp, sum(samples)::int8 AS samples,
+				max(staleness) AS staleness, bool_or("timestamp" IS NULL OR staleness > $4) AS stale
+			FROM quotes
+			GROUP BY symbol, added_at
+		), filtered AS (
+			SELECT *, row_number() OVER (ORDER BY %s) AS rn
+			FROM coins
+			WHERE $5::bool IS NULL OR stale = $5
+		), page AS (
+			SELECT symbol, added_at, rn FROM filtered WHERE rn > $7 AND rn <= $7 + $6
+		)
+		SELECT t.total, p.symbol, p.added_at, q.currency, q.price, q."precision", q."timestamp", q.samples
+		FROM (SELECT count(*) AS total FROM filtered) t
+		LEFT JOIN page p ON true
+		LEFT JOIN quotes q ON q.symbol = p.symbol
+		ORDER BY p.rn, q.currency;`
+
+	// Последняя цена каждой отслеживаемой пары и опорные сэмплы для окон $2 (сек): последний сэмпл не позже
+	// (время последней цены − окно) и не раньше (время последней цены − 2 × окно). Строка на пару и окно,
+	// окна нумеруются с 1; $1 — валюта котировки, пустое значение не фильтрует.
+	queryMarketTickers = `SELECT wc.symbol, c.currency,
+			last.price, last."precision", last."timestamp", last.market_cap, last.volume_24h, last.change_24h,
+			w.idx, ref.price, ref."precision", ref."timestamp"
+		FROM public.watched_currencies wc
+		CROSS JOIN LATERAL unnest(wc.currencies) AS c(currency)
+		JOIN LATERAL (
+			SELECT p.price, p."precision", p."timestamp", p.market_cap, p.volume_24h, p.change_24h
+			FROM public.currency_prices p
+			WHERE p.symbol = wc.symbol AND p.currency = c.currency
+			ORDER BY p."timestamp" DESC
+			LIMIT 1
+		) last ON true
+		CROSS JOIN unnest($2::int8[]) WITH ORDINALITY AS w(sec, idx)
+		LEFT JOIN LATERAL (
+			SELECT p.price, p."precision", p."timestamp"
+			FROM public.currency_prices p
+			WHERE p.symbol = wc.symbol AND p.currency = c.currency
+				AND p."timestamp" BETWEEN last."timestamp" - 2 * w.sec AND last."timestamp" - w.sec
+			ORDER BY p."timestamp" DESC
+			LIMIT 1
+		) ref ON true
+		WHERE $1 = '' OR c.currency = $1
+		ORDER BY wc.symbol, c.currency, w.idx;`
+
+	queryListRequest = `SELECT symbol, currencies FROM public.watched_currencies;`
+
+	// Источник возвращает одну и ту же метку времени, пока его данные не обновились. Повторный сэмпл с той же
+	// меткой не сохраняется: конфликт по уникальному ключу пропускается, и JobRepository.CoinDataUpdate
+	// определяет пропущенные строки по RowsAffected.
+	queryUpdatePriceCoin = `INSERT INTO public.currency_prices (symbol, "timestamp", price, "precision", currency, market_cap, volume_24h, change_24h) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (symbol, currency, "timestamp") DO NOTHING;`
+	queryInsertQuote = `INSERT INTO public.provider_quotes (symbol, provider, "timestamp", price, "precision", currency, is_outlier) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (symbol, provider, currency, "timestamp") DO NOTHING;`
+
+	// Соседние сэмплы: последний не позже $2 и первый не раньше $2 (при точном совпадении — одна и та же строка).
+	queryGetPriceAround = `(
+  		SELECT symbol, price, "precision", currency, "timestamp", market_cap, volume_24h, change_24h
   		FROM public.currency_prices
-  		WHERE symbol = $1 AND "timestamp" = $2
+  		WHERE symbol = $1 AND currency = $3 AND "timestamp" <= $2
+  		ORDER BY "timestamp" DESC
   		LIMIT 1
 	)
 	UNION ALL
 	(
-  		SELECT symbol, price, "precision", currency, "timestamp"
+  		SELECT symbol, price, "precision", currency, "timestamp", market_cap, volume_24h, change_24h
 		FROM public.currency_prices
-		WHERE symbol = $1 AND "timestamp" <> $2
-		ORDER BY ABS("timestamp" - $2)
+		WHERE symbol = $1 AND currency = $3 AND "timestamp" >= $2
+		ORDER BY "timestamp"
 		LIMIT 1
+	);`
+
+	// Соседние сэмплы для каждого элемента пакета: элементы передаются массивами и нумеруются с 1.
+	queryGetPricesAround = `SELECT r.idx,
+			b.price, b."precision", b."timestamp", b.market_cap, b.volume_24h, b.change_24h,
+			a.price, a."precision", a."timestamp", a.market_cap, a.volume_24h, a.change_24h
+		FROM unnest($1::text[], $2::int8[], $3::text[]) WITH ORDINALITY AS r(symbol, ts, currency, idx)
+		LEFT JOIN LATERAL (
+			SELECT p.price, p."precision", p."timestamp", p.market_cap, p.volume_24h, p.change_24h
+			FROM public.currency_prices p
+			WHERE p.symbol = r.symbol AND p.currency = r.currency AND p."timestamp" <= r.ts
+			ORDER BY p."timestamp" DESC
+			LIMIT 1
+		) b ON true
+		LEFT JOIN LATERAL (
+			SELECT p.price, p."precision", p."timestamp", p.market_cap, p.volume_24h, p.change_24h
+			FROM public.currency_prices p
+			WHERE p.symbol = r.symbol AND p.currency = r.currency AND p."timestamp" >= r.ts
+			ORDER BY p."timestamp"
+			LIMIT 1
+		) a ON true
+		ORDER BY r.idx;`
+
+	queryGetPriceBefore = `SELECT symbol, price, "precision", currency, "timestamp", market_cap, volume_24h, change_24h
+		FROM public.currency_prices
+		WHERE symbol = $1 AND currency = $3 AND "timestamp" <= $2
+		ORDER BY "timestamp" DESC
+		LIMIT 1;`
+
+	queryGetPriceAfter = `SELECT symbol, price, "precision", currency, "timestamp", market_cap, volume_24h, change_24h
+		FROM public.currency_prices
+		WHERE symbol = $1 AND currency = $3 AND "timestamp" >= $2
+		ORDER BY "timestamp"
+		LIMIT 1;`
+
+	queryGetHistoryForCoin = `SELECT symbol, price, "precision", currency, "timestamp", market_cap, volume_24h, change_24h
+		FROM public.currency_prices
+		WHERE symbol = $1 AND currency = $6 AND "timestamp" BETWEEN $2 AND $3 AND "timestamp" > $4
+		ORDER BY "timestamp"
+		LIMIT $5;`
+
+	// TWAP и VWAP за окно [$3, $4]. В расчёт входят сэмплы окна и последний сэмпл до его начала; сэмпл действует
+	// с max(timestamp, $3) до следующего сэмпла, конца окна или timestamp + $5 — что наступит раньше.
+	// Для VWAP объём за время действия сэмпла оценивается как volume_24h × время / 86400 (в валюте котировки),
+	// поэтому VWAP = Σ объём / Σ (объём / цена).
+	queryGetAveragePrice = `WITH samples AS (
+		(
+			SELECT "timestamp", price::numeric * power(10::numeric, -"precision") AS price, volume_24h
+			FROM public.currency_prices
+			WHERE symbol = $1 AND currency = $2 AND "timestamp" < $3
+			ORDER BY "timestamp" DESC
+			LIMIT 1
+		)
+		UNION ALL
+		(
+			SELECT "timestamp", price::numeric * power(10::numeric, -"precision") AS price, volume_24h
+			FROM public.currency_prices
+			WHERE symbol = $1 AND currency = $2 AND "timestamp" BETWEEN $3 AND $4
+		)
+	),
+	weighted AS (
+		SELECT price, volume_24h,
+			GREATEST(
+				LEAST(COALESCE(lead("timestamp") OVER (ORDER BY "timestamp"), $4::int8), $4::int8, "timestamp" + $5::int8)
+					- GREATEST("timestamp", $3::int8),
+				0
+			) AS weight
+		FROM samples
+	),
+	volumes AS (
+		SELECT price, weight, volume_24h * weight / 86400 AS volume
+		FROM weighted
+		WHERE weight > 0
 	)
-	LIMIT 1;`
+	SELECT sum(price * weight) / NULLIF(sum(weight), 0) AS twap,
+		count(*) AS samples,
+		COALESCE(sum(weight), 0)::int8 AS covered,
+		sum(volume) / NULLIF(sum(volume / NULLIF(price, 0)), 0) AS vwap,
+		sum(volume) AS volume,
+		count(volume) FILTER (WHERE volume > 0) AS volume_samples,
+		COALESCE(sum(weight) FILTER (WHERE volume > 0), 0)::int8 AS volume_covered
+	FROM volumes;`
+
+	// Цена закрытия (последний сэмпл) каждого непустого интервала длиной $4, начиная с $2, для монет $1.
+	queryGetClosesForCoins = `SELECT symbol, bucket, price, "precision"
+		FROM (
+			SELECT DISTINCT ON (symbol, bucket) symbol,
+				$2::int8 + (("timestamp" - $2::int8) / $4::int8) * $4::int8 AS bucket,
+				price, "precision"
+			FROM public.currency_prices
+			WHERE symbol = ANY($1::text[]) AND currency = $5 AND "timestamp" BETWEEN $2 AND $3
+			ORDER BY symbol, bucket, "timestamp" DESC
+		) closes
+		ORDER BY symbol, bucket;`
+
+	queryGetCandlesForCoin = `WITH buckets AS (
+		SELECT generate_series($2::int8, $3::int8, $4::int8) AS bucket
+	),
+	samples AS (
+		SELECT $2::int8 + (("timestamp" - $2::int8) / $4::int8) * $4::int8 AS bucket,
+			"timestamp",
+			price::numeric * power(10::numeric, -"precision") AS price
+		FROM public.currency_prices
+		WHERE symbol = $1 AND currency = $5 AND "timestamp" BETWEEN $2 AND $3
+	)
+	SELECT b.bucket,
+		(array_agg(s.price ORDER BY s."timestamp"))[1] AS open,
+		max(s.price) AS high,
+		min(s.price) AS low,
+		(array_agg(s.price ORDER BY s."timestamp" DESC))[1] AS close,
+		count(s.price) AS count
+	FROM buckets b
+	LEFT JOIN samples s ON s.bucket = b.bucket
+	GROUP BY b.bucket
+	ORDER BY b.bucket;`
+)
+
+const (
+	backfillColumns = `id, symbol, currency, provider, from_ts, to_ts, cursor_ts, status, fetched, inserted, error, created_at, updated_at`
+
+	queryCreateBackfill = `INSERT INTO public.backfill_jobs (symbol, currency, provider, from_ts, to_ts, cursor_ts, status)
+		VALUES ($1, $2, $3, $4, $5, $4, 'pending')
+		RETURNING ` + backfillColumns + `;`
+
+	// Незавершённая задача с теми же параметрами продолжается вместо создания новой.
+	queryFindActiveBackfill = `SELECT ` + backfillColumns + `
+		FROM public.backfill_jobs
+		WHERE symbol = $1 AND currency = $2 AND from_ts = $3 AND to_ts = $4 AND status IN ('pending', 'running')
+		ORDER BY id DESC
+		LIMIT 1;`
+
+	queryGetBackfill   = `SELECT ` + backfillColumns + ` FROM public.backfill_jobs WHERE id = $1;`
+	queryListBackfills = `SELECT ` + backfillColumns + ` FROM public.backfill_jobs ORDER BY id DESC LIMIT $1;`
+
+	// Задача берётся в работу, если она ожидает запуска или её исполнитель не отчитывался дольше $1 секунд.
+	queryClaimBackfill = `UPDATE public.backfill_jobs SET status = 'running', updated_at = now()
+		WHERE id = (
+			SELECT id FROM public.backfill_jobs
+			WHERE status = 'pending' OR (status = 'running' AND updated_at < now() - make_interval(secs => $1))
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + backfillColumns + `;`
+
+	queryClaimBackfillByID = `UPDATE public.backfill_jobs SET status = 'running', error = '', updated_at = now()
+		WHERE id = $1 AND (status IN ('pending', 'failed') OR (status = 'running' AND updated_at < now() - make_interval(secs => $2)))
+		RETURNING ` + backfillColumns + `;`
+
+	queryAdvanceBackfill = `UPDATE public.backfill_jobs
+		SET cursor_ts = $2, fetched = fetched + $3, inserted = inserted + $4, updated_at = now()
+		WHERE id = $1;`
+
+	queryFinishBackfill = `UPDATE public.backfill_jobs SET status = $2, error = $3, updated_at = now() WHERE id = $1;`
+)
+
+const (
+	gapColumns = `id, symbol, currency, gap_start, gap_end, duration, status, backfill_id, detected_at, updated_at`
+
+	// Пропуском считается интервал между соседними ценами пары (symbol, currency) длиннее $2 секунд,
+	// а также интервал от последней цены до текущего момента (пропуск в конце, пока цены не поступают).
+	// Сканируются цены начиная с $1, но не раньше добавления монеты в отслеживание: история,
+	// загруженная до этого момента, имеет более редкую детализацию источника. Пропуск в конце ищется
+	// от последней цены пары, даже если она раньше $1.
+	// Пропуск, начинающийся с той же цены, что и уже найденный, не дублируется: открытый пропуск в конце
+	// продлевается до текущего момента или до поступившей цены.
+	queryDetectGaps = `WITH watched AS (
+			SELECT wc.symbol, c.currency, GREATEST($1::int8, COALESCE(extract(epoch FROM wc.added_at)::int8, 0)) AS since
+			FROM public.watched_currencies wc, unnest(wc.currencies) AS c(currency)
+		), samples AS (
+			SELECT p.symbol, p.currency, p."timestamp",
+				lag(p."timestamp") OVER (PARTITION BY p.symbol, p.currency ORDER BY p."timestamp") AS previous
+			FROM public.currency_prices p
+			JOIN watched w ON w.symbol = p.symbol AND w.currency = p.currency
+			WHERE p."timestamp" >= w.since
+		), found AS (
+			SELECT symbol, currency, previous AS gap_start, "timestamp" AS gap_end
+			FROM samples
+			WHERE previous IS NOT NULL AND "timestamp" - previous > $2
+			UNION ALL
+			SELECT w.symbol, w.currency, last.ts, extract(epoch FROM now())::int8
+			FROM watched w
+			CROSS JOIN LATERAL (
+				SELECT max(p."timestamp") AS ts
+				FROM public.currency_prices p
+				WHERE p.symbol = w.symbol AND p.currency = w.currency
+			) last
+			WHERE extract(epoch FROM now())::int8 - last.ts > $2
+		), extended AS (
+			UPDATE public.price_gaps g
+			SET gap_end = f.gap_end, duration = f.gap_end - f.gap_start, updated_at = now()
+			FROM found f
+			WHERE g.symbol = f.symbol AND g.currency = f.currency AND g.gap_start = f.gap_start
+				AND g.gap_end <> f.gap_end AND g.status = 'open'
+		)
+		INSERT INTO public.price_gaps (symbol, currency, gap_start, gap_end, duration)
+		SELECT f.symbol, f.currency, f.gap_start, f.gap_end, f.gap_end - f.gap_start
+		FROM found f
+		WHERE NOT EXISTS (
+			SELECT 1 FROM public.price_gaps g
+			WHERE g.symbol = f.symbol AND g.currency = f.currency AND g.gap_start = f.gap_start
+		)
+		ON CONFLICT (symbol, currency, gap_start, gap_end) DO NOTHING
+		RETURNING ` + gapColumns + `;`
+
+	queryListGaps = `SELECT ` + gapColumns + `
+		FROM public.price_gaps
+		WHERE ($1 = '' OR symbol = $1) AND ($2 = '' OR currency = $2) AND ($3 = '' OR status = $3)
+		ORDER BY gap_start DESC, id DESC
+		LIMIT $4;`
+
+	queryGetGap = `SELECT ` + gapColumns + ` FROM public.price_gaps WHERE id = $1;`
+
+	queryAttachGapBackfill = `UPDATE public.price_gaps SET status = 'repairing', backfill_id = $2, updated_at = now()
+		WHERE id = $1 AND status IN ('open', 'failed', 'unrepairable')
+		RETURNING ` + gapColumns + `;`
+
+	// Статус пропуска в ремонте определяется по завершившейся задаче дозагрузки: после успешной задачи интервал
+	// пропуска сканируется заново вместе с его границами, и если в нём остался интервал между соседними ценами
+	// длиннее $1 (порог пропуска или шаг истории источника, если он больше), пропуск считается неустранимым
+	// (источник не отдал историю за этот период).
+	querySyncGapStatuses = `UPDATE public.price_gaps g
+		SET status = CASE
+				WHEN b.status = 'failed' THEN 'failed'
+				WHEN EXISTS (
+					SELECT 1
+					FROM (
+						SELECT ts - lag(ts) OVER (ORDER BY ts) AS step
+						FROM (
+							SELECT p."timestamp" AS ts
+							FROM public.currency_prices p
+							WHERE p.symbol = g.symbol AND p.currency = g.currency AND p."timestamp" BETWEEN g.gap_start AND g.gap_end
+							UNION
+							SELECT unnest(ARRAY[g.gap_start, g.gap_end])
+						) points
+					) steps
+					WHERE step > $1
+				) THEN 'unrepairable'
+				ELSE 'repaired'
+			END,
+			updated_at = now()
+		FROM public.backfill_jobs b
+		WHERE g.backfill_id = b.id AND g.status = 'repairing' AND b.status IN ('done', 'failed');`
+)
+
+const (
+	alertColumns    = `id, symbol, currency, "condition", threshold, window_sec, webhook_url, secret, enabled, triggered, last_triggered_at, created_at`
+	deliveryColumns = `d.id, d.rule_id, d.payload, d.status, d.attempts, d.last_status_code, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at`
+
+	queryCreateAlert = `INSERT INTO public.alert_rules (symbol, currency, "condition", threshold, window_sec, webhook_url, secret)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + alertColumns + `;`
+
+	queryGetAlert    = `SELECT ` + alertColumns + ` FROM public.alert_rules WHERE id = $1;`
+	queryListAlerts  = `SELECT ` + alertColumns + ` FROM public.alert_rules ORDER BY id DESC;`
+	queryDeleteAlert = `DELETE FROM public.alert_rules WHERE id = $1;`
+
+	queryActiveAlerts = `SELECT ` + alertColumns + `
+		FROM public.alert_rules
+		WHERE enabled AND symbol = ANY($1::text[])
+		ORDER BY id;`
+
+	// Правило срабатывает только при переходе из состояния triggered = false,
+	// поэтому повторная проверка того же тика не создаёт второе оповещение.
+	queryFireAlert = `WITH fired AS (
+			UPDATE public.alert_rules SET triggered = true, last_triggered_at = $2
+			WHERE id = $1 AND NOT triggered
+			RETURNING id
+		)
+		INSERT INTO public.alert_deliveries (rule_id, payload)
+		SELECT id, $3::jsonb FROM fired;`
+
+	queryRearmAlert = `UPDATE public.alert_rules SET triggered = false WHERE id = $1 AND triggered;`
+
+	queryListDeliveries = `SELECT ` + deliveryColumns + `
+		FROM public.alert_deliveries d
+		WHERE d.rule_id = $1
+		ORDER BY d.id DESC
+		LIMIT $2;`
+
+	// Оповещения берутся в работу со сдвигом next_attempt_at на $2 секунд: если отправитель
+	// завершится, не записав результат, оповещение будет отправлено повторно после этого срока.
+	queryClaimDeliveries = `UPDATE public.alert_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM public.alert_rules r
+		WHERE r.id = d.rule_id AND d.id IN (
+			SELECT id FROM public.alert_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, r.webhook_url, r.secret;`
+
+	queryRecordDelivery = `UPDATE public.alert_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4,
+			next_attempt_at = now() + make_interval(secs => $5),
+			delivered_at = CASE WHEN $2 = 'delivered' THEN now() END
+		WHERE id = $1;`
+)
+
+const (
+	queryClearRegistry = `DELETE FROM public.coin_registry;`
+	queryLoadRegistry  = `SELECT id, symbol, "name", market_cap_rank, updated_at
+		FROM public.coin_registry
+		ORDER BY market_cap_rank NULLS LAST, id;`
 )
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type RegistryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRegistryRepository(db *pgxpool.Pool) *RegistryRepository {
+	return &RegistryRepository{
+		db: db,
+	}
+}
+
+// SaveRegistry заменяет сохранённую копию реестра монет в одной транзакции.
+func (r *RegistryRepository) SaveRegistry(ctx context.Context, coins []*models.CoinInfo) error {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	tx, err := r.db.Begin(dbCtx)
+	if err != nil {
+		zap.L().Error("begin transaction error", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback(dbCtx)
+
+	if _, err := tx.Exec(dbCtx, queryClearRegistry); err != nil {
+		zap.L().Error("clear coin registry error", zap.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.CopyFrom(dbCtx,
+		pgx.Identifier{"public", "coin_registry"},
+		[]string{"id", "symbol", "name", "market_cap_rank", "updated_at"},
+		pgx.CopyFromSlice(len(coins), func(i int) ([]any, error) {
+			return []any{coins[i].ID, coins[i].Symbol, coins[i].Name, coins[i].MarketCapRank, now}, nil
+		}),
+	)
+	if err != nil {
+		zap.L().Error("copy coin registry error", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(dbCtx); err != nil {
+		zap.L().Error("commit transaction error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// LoadRegistry возвращает сохранённую копию реестра монет и время её сохранения.
+func (r *RegistryRepository) LoadRegistry(ctx context.Context) ([]*models.CoinInfo, time.Time, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, common.PostgresDBQueryTimeout)
+	defer cancel()
+
+	rows, err := r.db.Query(dbCtx, queryLoadRegistry)
+	if err != nil {
+		zap.L().Error("Error loading coin registry", zap.Error(err))
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var updatedAt time.Time
+	coins := make([]*models.CoinInfo, 0)
+	for rows.Next() {
+		coin := new(models.CoinInfo)
+		var savedAt time.Time
+		if err := rows.Scan(&coin.ID, &coin.Symbol, &coin.Name, &coin.MarketCapRank, &savedAt); err != nil {
+			zap.L().Error("Scan error", zap.Error(err))
+			return nil, time.Time{}, err
+		}
+		if savedAt.After(updatedAt) {
+			updatedAt = savedAt
+		}
+		coins = append(coins, coin)
+	}
+	if err := rows.Err(); err != nil {
+		zap.L().Error("Error loading coin registry", zap.Error(err))
+		return nil, time.Time{}, err
+	}
+	return coins, updatedAt, nil
+}
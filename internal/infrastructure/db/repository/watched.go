@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+)
+
+// addWatchedQuote рассчитывает устарелость цены пары на момент now и добавляет пару к монете,
+// обновляя сводные значения монеты.
+func addWatchedQuote(coin *models.WatchedCoinInfo, quote *models.WatchedQuote, now, staleAfter int64) {
+	quote.Stale = true
+	if quote.LastTimestamp != nil {
+		staleness := max(now-*quote.LastTimestamp, common.Zero)
+		quote.Staleness = &staleness
+		quote.Stale = staleness > staleAfter
+	}
+
+	coin.Quotes = append(coin.Quotes, quote)
+	coin.Samples += quote.Samples
+	coin.Stale = coin.Stale || quote.Stale
+	if quote.LastTimestamp != nil && (coin.LastTimestamp == nil || *quote.LastTimestamp > *coin.LastTimestamp) {
+		coin.LastTimestamp = quote.LastTimestamp
+	}
+	if quote.Staleness != nil && (coin.Staleness == nil || *quote.Staleness > *coin.Staleness) {
+		coin.Staleness = quote.Staleness
+	}
+}
+
+// watchedSortColumns — столбцы сводных значений монеты в queryListWatchedCoins для параметра sort.
+var watchedSortColumns = map[string]string{
+	common.SortCoin:          "symbol",
+	common.SortAddedAt:       "added_at",
+	common.SortLastTimestamp: "last_timestamp",
+	common.SortSamples:       "samples",
+	common.SortStaleness:     "staleness",
+}
+
+// watchedOrder возвращает выражение ORDER BY для сортировки монет запроса; при равенстве монеты
+// упорядочиваются по ID в том же направлении. Монеты без значения поля сортировки располагаются
+// в конце при любом порядке.
+func watchedOrder(req *models.WatchedCoinRequest) string {
+	column, ok := watchedSortColumns[req.Sort]
+	if !ok {
+		column = watchedSortColumns[common.SortCoin]
+	}
+	direction := "ASC"
+	if req.Order == common.OrderDesc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, symbol %s", column, direction, direction)
+}
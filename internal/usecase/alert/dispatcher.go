@@ -0,0 +1,102 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Dispatcher отправляет оповещения из журнала доставки на вебхуки.
+//
+// Неудачная попытка повторяется с экспоненциальной задержкой от AlertRetryBaseDelay до
+// AlertRetryMaxDelay со случайным джиттером; после AlertMaxAttempts попыток оповещение
+// получает статус failed. Результат каждой попытки сохраняется в журнале.
+type Dispatcher struct {
+	alertRepository interfaces.AlertRepositoryI
+	webhookClient   interfaces.WebhookClientI
+}
+
+// NewDispatcher создаёт новую задачу доставки оповещений.
+//
+// Параметры:
+//   - alertRepository: репозиторий правил и журнала доставки
+//   - webhookClient: клиент отправки на вебхуки
+//
+// Возвращает указатель на Dispatcher.
+func NewDispatcher(alertRepository interfaces.AlertRepositoryI, webhookClient interfaces.WebhookClientI) *Dispatcher {
+	return &Dispatcher{
+		alertRepository: alertRepository,
+		webhookClient:   webhookClient,
+	}
+}
+
+// Run отправляет оповещения, срок отправки которых наступил, пока очередь не опустеет.
+func (d *Dispatcher) Run() {
+	ctx := context.Background()
+
+	for {
+		deliveries, err := d.alertRepository.ClaimDeliveries(ctx, common.AlertDispatchBatch)
+		if err != nil {
+			zap.L().Error("ClaimDeliveries failed", zap.Error(err))
+			return
+		}
+		if len(deliveries) == common.Zero {
+			return
+		}
+
+		g := new(errgroup.Group)
+		g.SetLimit(common.AlertDispatchWorkers)
+		for _, delivery := range deliveries {
+			g.Go(func() error {
+				d.deliver(ctx, delivery)
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		if len(deliveries) < common.AlertDispatchBatch {
+			return
+		}
+	}
+}
+
+// deliver выполняет одну попытку отправки и сохраняет её результат.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.AlertDelivery) {
+	code, err := d.webhookClient.Send(ctx, delivery)
+	if err == nil {
+		zap.L().Info("Alert delivered", zap.Int64("id:", delivery.ID), zap.Int64("rule:", delivery.RuleID))
+		if err := d.alertRepository.RecordDelivery(ctx, delivery.ID, common.DeliveryDelivered, code, common.Empty, 0); err != nil {
+			zap.L().Error("RecordDelivery failed", zap.Error(err), zap.Int64("id:", delivery.ID))
+		}
+		return
+	}
+
+	attempt := delivery.Attempts + 1
+	status := common.DeliveryPending
+	if attempt >= common.AlertMaxAttempts || errors.Is(err, common.ErrWebhookBlocked) {
+		// Запрещённый адрес не станет доступным при повторе.
+		status = common.DeliveryFailed
+	}
+	zap.L().Warn("Alert delivery failed", zap.Error(err), zap.Int64("id:", delivery.ID),
+		zap.Int64("rule:", delivery.RuleID), zap.Int("attempt:", attempt), zap.String("status:", status))
+
+	if err := d.alertRepository.RecordDelivery(ctx, delivery.ID, status, code, err.Error(), backoff(attempt)); err != nil {
+		zap.L().Error("RecordDelivery failed", zap.Error(err), zap.Int64("id:", delivery.ID))
+	}
+}
+
+// backoff возвращает задержку перед повтором после attempt неудачных попыток.
+func backoff(attempt int) time.Duration {
+	delay := common.AlertRetryMaxDelay
+	if attempt < 30 {
+		delay = min(common.AlertRetryBaseDelay<<(attempt-1), common.AlertRetryMaxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,217 @@
+package alert
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Service управляет правилами оповещений и проверяет их после каждого сохранения цен.
+//
+// Правило срабатывает один раз при выполнении условия и снова становится активным,
+// когда условие перестаёт выполняться. Оповещения записываются в журнал доставки
+// и отправляются на вебхуки задачей Dispatcher.
+type Service struct {
+	alertRepository interfaces.AlertRepositoryI
+	majorRepository interfaces.MajorRepositoryI
+	webhookClient   interfaces.WebhookClientI
+}
+
+// NewService создаёт новый сервис оповещений.
+//
+// Параметры:
+//   - alertRepository: репозиторий правил и журнала доставки
+//   - majorRepository: репозиторий цен для условия change
+//   - webhookClient: клиент вебхуков, проверяющий адрес вебхука при создании правила
+//
+// Возвращает указатель на Service.
+func NewService(alertRepository interfaces.AlertRepositoryI, majorRepository interfaces.MajorRepositoryI, webhookClient interfaces.WebhookClientI) *Service {
+	return &Service{
+		alertRepository: alertRepository,
+		majorRepository: majorRepository,
+		webhookClient:   webhookClient,
+	}
+}
+
+// Create сохраняет правило. Если секрет не задан, генерируется случайный;
+// секрет возвращается только в ответе на создание.
+//
+// Возвращает ErrInvalidAlert, если адрес вебхука запрещён (внутренняя сеть, не разрешённая в WEBHOOK_ALLOW_LIST).
+func (s *Service) Create(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error) {
+	webhook, err := s.webhookClient.Validate(ctx, rule.WebhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid webhook_url: %w", common.ErrInvalidAlert, err)
+	}
+	rule.WebhookURL = webhook
+
+	if rule.Secret == common.Empty {
+		secret := make([]byte, common.AlertSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		rule.Secret = hex.EncodeToString(secret)
+	}
+	return s.alertRepository.CreateAlert(ctx, rule)
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (*models.AlertRule, error) {
+	rule, err := s.alertRepository.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rule.Secret = common.Empty
+	return rule, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*models.AlertRule, error) {
+	rules, err := s.alertRepository.ListAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		rule.Secret = common.Empty
+	}
+	return rules, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.alertRepository.DeleteAlert(ctx, id)
+}
+
+// Deliveries возвращает журнал доставки правила от новых записей к старым.
+func (s *Service) Deliveries(ctx context.Context, id int64, limit int) ([]*models.AlertDelivery, error) {
+	if _, err := s.alertRepository.GetAlert(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.alertRepository.ListDeliveries(ctx, id, limit)
+}
+
+// Publish проверяет правила по новым ценам. Вызывается задачей загрузки после сохранения цен.
+func (s *Service) Publish(events []*models.PriceEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.ReqTimeout)
+	defer cancel()
+
+	evaluation, err := s.evaluate(ctx, events)
+	if err != nil {
+		zap.L().Error("Alert evaluation failed", zap.Error(err))
+		return
+	}
+	if err := s.alertRepository.SaveEvaluation(ctx, evaluation); err != nil {
+		zap.L().Error("SaveEvaluation failed", zap.Error(err))
+		return
+	}
+	for _, event := range evaluation.Fired {
+		zap.L().Info("Alert triggered", zap.Int64("id:", event.AlertID), zap.String("name:", event.Coin),
+			zap.String("currency:", event.Currency), zap.String("price:", event.Price.String()))
+	}
+}
+
+// evaluate сопоставляет правила с новыми ценами и определяет сработавшие и вновь активные правила.
+// Правила без новой цены своей пары и правила change без цены начала окна не меняют состояние.
+func (s *Service) evaluate(ctx context.Context, events []*models.PriceEvent) (*models.AlertEvaluation, error) {
+	evaluation := new(models.AlertEvaluation)
+
+	latest := make(map[string]*models.PriceEvent, len(events))
+	seen := make(map[string]bool, len(events))
+	coins := make([]string, 0, len(events))
+	for _, event := range events {
+		key := event.Coin + "/" + event.Currency
+		if previous, ok := latest[key]; !ok || previous.Timestamp <= event.Timestamp {
+			latest[key] = event
+		}
+		if !seen[event.Coin] {
+			seen[event.Coin] = true
+			coins = append(coins, event.Coin)
+		}
+	}
+
+	rules, err := s.alertRepository.ActiveAlerts(ctx, coins)
+	if err != nil {
+		return nil, err
+	}
+
+	// Цены начала окна для правил change запрашиваются одним пакетом.
+	matched := make([]*models.AlertRule, 0, len(rules))
+	bases := make([]*models.PriceRequest, 0)
+	for _, rule := range rules {
+		event := latest[rule.Coin+"/"+rule.Currency]
+		if event == nil {
+			continue
+		}
+		matched = append(matched, rule)
+		if rule.Condition == common.AlertChange {
+			bases = append(bases, &models.PriceRequest{
+				Coin:        rule.Coin,
+				Currency:    rule.Currency,
+				Timestamp:   event.Timestamp - rule.Window,
+				Mode:        common.ModeBefore,
+				MaxDistance: rule.Window,
+			})
+		}
+	}
+
+	var (
+		basePrices []*models.PriceResponse
+		baseErrs   []error
+	)
+	if len(bases) > common.Zero {
+		basePrices, baseErrs, err = s.majorRepository.GetPrices(ctx, bases)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next := common.Zero
+	for _, rule := range matched {
+		event := latest[rule.Coin+"/"+rule.Currency]
+		alertEvent := &models.AlertEvent{
+			AlertID:   rule.ID,
+			Coin:      rule.Coin,
+			Currency:  rule.Currency,
+			Condition: rule.Condition,
+			Threshold: models.Number{Decimal: rule.Threshold.Decimal},
+			Window:    rule.Window,
+			Price:     models.Number{Decimal: event.Price.Decimal},
+			Timestamp: event.Timestamp,
+		}
+
+		var hit bool
+		switch rule.Condition {
+		case common.AlertAbove:
+			hit = event.Price.GreaterThan(rule.Threshold.Decimal)
+		case common.AlertBelow:
+			hit = event.Price.LessThan(rule.Threshold.Decimal)
+		case common.AlertChange:
+			i := next
+			next++
+			if baseErrs[i] != nil || basePrices[i] == nil || basePrices[i].Price.IsZero() {
+				continue
+			}
+			base := basePrices[i]
+			change := event.Price.Sub(base.Price.Decimal).Mul(hundred).DivRound(base.Price.Decimal, common.RatePrecision)
+			hit = change.Abs().GreaterThanOrEqual(rule.Threshold.Decimal)
+			alertEvent.BasePrice = &models.Number{Decimal: base.Price.Decimal}
+			alertEvent.BaseTimestamp = &base.Timestamp
+			alertEvent.ChangePercent = &models.Number{Decimal: change}
+		default:
+			continue
+		}
+
+		switch {
+		case hit && !rule.Triggered:
+			evaluation.Fired = append(evaluation.Fired, alertEvent)
+		case !hit && rule.Triggered:
+			evaluation.Rearmed = append(evaluation.Rearmed, rule.ID)
+		}
+	}
+	return evaluation, nil
+}
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+)
+
+// Service рассчитывает аналитику по сохранённым ценам нескольких монет.
+//
+// Статистики считаются в float64: доходности и корреляции — оценки, а не цены,
+// и для них нужны логарифм и корень, которых нет в точной десятичной арифметике.
+type Service struct {
+	majorRepository interfaces.MajorRepositoryI
+}
+
+// NewService создаёт сервис аналитики.
+//
+// Параметры:
+//   - majorRepository: репозиторий, из которого берутся цены закрытия интервалов
+//
+// Возвращает указатель на Service.
+func NewService(majorRepository interfaces.MajorRepositoryI) *Service {
+	return &Service{
+		majorRepository: majorRepository,
+	}
+}
+
+// Correlation рассчитывает матрицы корреляции Пирсона и Спирмена и ковариации доходностей монет req.CoinList.
+//
+// Цены закрытия приводятся к общей сетке интервалов длиной req.Step, начиная с req.From, выровненного по границе
+// интервала. Доходность интервала считается только между соседними интервалами с ценами, без переноса цен через
+// пропуски. В расчёт попадают интервалы, где есть доходность каждой монеты, поэтому все матрицы построены
+// по одним и тем же наблюдениям.
+//
+// Возвращает ErrNotEnoughData, если таких интервалов меньше MinCorrelationObservations.
+func (s *Service) Correlation(ctx context.Context, req *models.CorrelationRequest) (*models.CorrelationResponse, error) {
+	start := req.From - req.From%req.Step
+
+	closes, err := s.majorRepository.GetCloses(ctx, req.CoinList, req.Currency, start, req.To, req.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	returns := make([]map[int64]float64, len(req.CoinList))
+	for i, coin := range req.CoinList {
+		returns[i] = returnSeries(closes[coin], req.Step, req.Returns)
+	}
+
+	// Общие интервалы: доходности первой монеты в порядке времени, присутствующие у всех остальных.
+	buckets := make([]int64, 0)
+	for _, point := range closes[req.CoinList[0]] {
+		if _, ok := returns[0][point.Timestamp]; !ok {
+			continue
+		}
+		aligned := true
+		for _, series := range returns[1:] {
+			if _, ok := series[point.Timestamp]; !ok {
+				aligned = false
+				break
+			}
+		}
+		if aligned {
+			buckets = append(buckets, point.Timestamp)
+		}
+	}
+	if len(buckets) < common.MinCorrelationObservations {
+		return nil, fmt.Errorf("%w: %d of %d required", common.ErrNotEnoughData, len(buckets), common.MinCorrelationObservations)
+	}
+
+	series := make([][]float64, len(req.CoinList))
+	ranked := make([][]float64, len(req.CoinList))
+	for i := range req.CoinList {
+		series[i] = make([]float64, len(buckets))
+		for j, bucket := range buckets {
+			series[i][j] = returns[i][bucket]
+		}
+		ranked[i] = ranks(series[i])
+	}
+
+	return &models.CorrelationResponse{
+		Coins:        req.CoinList,
+		Currency:     req.Currency,
+		Interval:     req.Step,
+		From:         start,
+		To:           req.To,
+		Returns:      req.Returns,
+		Observations: len(buckets),
+		Pearson:      matrix(series, pearson),
+		Spearman:     matrix(ranked, pearson),
+		Covariance:   matrix(series, covariance),
+	}, nil
+}
+
+// returnSeries рассчитывает доходности интервалов по ценам закрытия: ключ — начало интервала, доходность
+// которого считается от цены закрытия предыдущего интервала. Интервалы без предыдущей цены и с неположительными
+// ценами пропускаются.
+func returnSeries(points []*models.PricePoint, step int64, kind string) map[int64]float64 {
+	result := make(map[int64]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		previous, current := points[i-1], points[i]
+		if current.Timestamp-previous.Timestamp != step || !previous.Price.IsPositive() || !current.Price.IsPositive() {
+			continue
+		}
+		ratio := current.Price.InexactFloat64() / previous.Price.InexactFloat64()
+		if kind == common.ReturnsSimple {
+			result[current.Timestamp] = ratio - 1
+		} else {
+			result[current.Timestamp] = math.Log(ratio)
+		}
+	}
+	return result
+}
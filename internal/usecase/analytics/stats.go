@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+)
+
+// mean возвращает среднее значений.
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// covariance возвращает выборочную ковариацию (делитель n − 1) двух рядов одинаковой длины.
+func covariance(x, y []float64) (float64, bool) {
+	if len(x) < 2 {
+		return 0, false
+	}
+	meanX, meanY := mean(x), mean(y)
+
+	var sum float64
+	for i := range x {
+		sum += (x[i] - meanX) * (y[i] - meanY)
+	}
+	return sum / float64(len(x)-1), true
+}
+
+// pearson возвращает коэффициент корреляции Пирсона; для ряда без изменений корреляция не определена.
+func pearson(x, y []float64) (float64, bool) {
+	cov, ok := covariance(x, y)
+	if !ok {
+		return 0, false
+	}
+	varX, _ := covariance(x, x)
+	varY, _ := covariance(y, y)
+	if varX <= 0 || varY <= 0 {
+		return 0, false
+	}
+	// Ограничение отрезком [-1, 1] убирает погрешность округления у почти линейно зависимых рядов.
+	return max(-1, min(1, cov/math.Sqrt(varX*varY))), true
+}
+
+// ranks возвращает ранги значений начиная с 1; одинаковым значениям присваивается средний ранг.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case values[a] < values[b]:
+			return -1
+		case values[a] > values[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	result := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		// Позиции start..end-1 соответствуют рангам start+1..end, их среднее — (start+end+1)/2.
+		rank := float64(start+end+1) / 2
+		for _, index := range order[start:end] {
+			result[index] = rank
+		}
+		start = end
+	}
+	return result
+}
+
+// matrix строит симметричную матрицу значений fn для всех пар рядов; неопределённые значения равны nil.
+func matrix(series [][]float64, fn func(x, y []float64) (float64, bool)) [][]*float64 {
+	result := make([][]*float64, len(series))
+	for i := range series {
+		result[i] = make([]*float64, len(series))
+	}
+	for i := range series {
+		for j := i; j < len(series); j++ {
+			value, ok := fn(series[i], series[j])
+			if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			result[i][j], result[j][i] = &value, &value
+		}
+	}
+	return result
+}
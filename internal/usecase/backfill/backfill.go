@@ -0,0 +1,215 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Backfiller дозагружает историю цен монет из источника, поддерживающего запросы за период.
+//
+// Задача обрабатывается частями по BackfillChunkWindow: после каждой части точки и курсор
+// сохраняются в одной транзакции, поэтому прерванная задача продолжается с места остановки.
+type Backfiller struct {
+	backfillRepository interfaces.BackfillRepositoryI
+	registryClient     interfaces.RegistryClientI
+}
+
+// NewBackfiller создаёт новый сервис дозагрузки истории.
+//
+// Параметры:
+//   - backfillRepository: репозиторий задач и точек истории
+//   - registryClient: клиент, предоставляющий источник истории цен
+//
+// Возвращает указатель на Backfiller.
+func NewBackfiller(backfillRepository interfaces.BackfillRepositoryI, registryClient interfaces.RegistryClientI) *Backfiller {
+	return &Backfiller{
+		backfillRepository: backfillRepository,
+		registryClient:     registryClient,
+	}
+}
+
+// Enqueue создаёт задачу дозагрузки истории.
+//
+// По умолчанию to — текущее время, from — to минус DefaultBackfillWindow; to не может быть в будущем.
+// Если незавершённая задача с теми же параметрами уже есть, возвращается она.
+//
+// Возвращает ErrInvalidRange, ErrUnsupportedCurrency или ErrNoHistoryProvider при некорректном запросе.
+func (b *Backfiller) Enqueue(ctx context.Context, req *models.BackfillRequest) (*models.BackfillJob, error) {
+	currency, err := common.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	to := req.To
+	if to == common.Zero || to > now {
+		to = now
+	}
+	from := req.From
+	if from == common.Zero {
+		from = to - common.DefaultBackfillWindow
+	}
+	if from < common.Zero || from >= to {
+		return nil, common.ErrInvalidRange
+	}
+
+	provider, err := b.registryClient.HistoryProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.backfillRepository.FindActiveBackfill(ctx, req.Coin, currency, from, to)
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, common.ErrBackfillNotFound) {
+		return nil, err
+	}
+
+	return b.backfillRepository.CreateBackfill(ctx, &models.BackfillJob{
+		Coin:     req.Coin,
+		Currency: currency,
+		Provider: provider.Name(),
+		From:     from,
+		To:       to,
+	})
+}
+
+func (b *Backfiller) Get(ctx context.Context, id int64) (*models.BackfillJob, error) {
+	return b.backfillRepository.GetBackfill(ctx, id)
+}
+
+func (b *Backfiller) List(ctx context.Context, limit int) ([]*models.BackfillJob, error) {
+	return b.backfillRepository.ListBackfills(ctx, limit)
+}
+
+// Retry возвращает задачу в статусе failed в очередь; загрузка продолжится с сохранённого курсора.
+//
+// Возвращает ErrBackfillNotClaimed, если задача не в статусе failed.
+func (b *Backfiller) Retry(ctx context.Context, id int64) (*models.BackfillJob, error) {
+	job, err := b.backfillRepository.GetBackfill(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != common.BackfillFailed {
+		return nil, common.ErrBackfillNotClaimed
+	}
+	if err := b.backfillRepository.FinishBackfill(ctx, id, common.BackfillPending, common.Empty); err != nil {
+		return nil, err
+	}
+	job.Status = common.BackfillPending
+	job.Error = common.Empty
+	return job, nil
+}
+
+// Run обрабатывает ожидающие и прерванные задачи по очереди, пока они не закончатся.
+func (b *Backfiller) Run() {
+	ctx := context.Background()
+
+	for {
+		job, err := b.backfillRepository.ClaimBackfill(ctx)
+		if err != nil {
+			if !errors.Is(err, common.ErrBackfillNotFound) {
+				zap.L().Error("ClaimBackfill failed", zap.Error(err))
+			}
+			return
+		}
+		if err := b.Process(ctx, job); err != nil {
+			zap.L().Error("Backfill failed", zap.Error(err), zap.Int64("id:", job.ID), zap.String("name:", job.Coin))
+		}
+	}
+}
+
+// Resume берёт в работу указанную задачу и выполняет её до конца.
+//
+// Возвращает ErrBackfillNotClaimed, если задача уже завершена или выполняется другим процессом.
+func (b *Backfiller) Resume(ctx context.Context, id int64) (*models.BackfillJob, error) {
+	job, err := b.backfillRepository.ClaimBackfillByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Process(ctx, job); err != nil {
+		return job, err
+	}
+	return b.backfillRepository.GetBackfill(ctx, id)
+}
+
+// HistoryStep возвращает интервал между точками истории источника дозагрузки (сек)
+// или 0, если такого источника нет.
+func (b *Backfiller) HistoryStep() int64 {
+	provider, err := b.registryClient.HistoryProvider()
+	if err != nil {
+		return common.Zero
+	}
+	return provider.HistoryStep()
+}
+
+// Process загружает историю задачи начиная с курсора и устанавливает итоговый статус.
+//
+// При отмене контекста задача возвращается в статус pending, чтобы её можно было продолжить;
+// при ошибке источника или базы — помечается failed с текстом ошибки.
+func (b *Backfiller) Process(ctx context.Context, job *models.BackfillJob) error {
+	provider, err := b.registryClient.HistoryProvider()
+	if err != nil {
+		return b.finish(job, common.BackfillFailed, err)
+	}
+
+	zap.L().Info("Starting backfill", zap.Int64("id:", job.ID), zap.String("name:", job.Coin),
+		zap.String("currency:", job.Currency), zap.Int64("cursor:", job.Cursor), zap.Int64("to:", job.To))
+
+	for job.Cursor < job.To {
+		end := min(job.Cursor+common.BackfillChunkWindow, job.To)
+
+		points, err := provider.PriceRange(ctx, job.Coin, job.Currency, job.Cursor, end)
+		if err != nil {
+			return b.interrupt(ctx, job, err)
+		}
+
+		inserted, err := b.backfillRepository.SaveBackfillChunk(ctx, job, points, end)
+		if err != nil {
+			return b.interrupt(ctx, job, err)
+		}
+		job.Cursor = end
+		job.Fetched += int64(len(points))
+		job.Inserted += inserted
+
+		zap.L().Info("Backfill progress", zap.Int64("id:", job.ID), zap.Int64("cursor:", job.Cursor),
+			zap.Int("fetched:", len(points)), zap.Int64("inserted:", inserted))
+	}
+
+	zap.L().Info("Backfill done", zap.Int64("id:", job.ID), zap.Int64("fetched:", job.Fetched), zap.Int64("inserted:", job.Inserted))
+	return b.finish(job, common.BackfillDone, nil)
+}
+
+// interrupt завершает обработку части задачи: при отмене контекста задача остаётся для продолжения.
+func (b *Backfiller) interrupt(ctx context.Context, job *models.BackfillJob, err error) error {
+	if ctx.Err() != nil {
+		zap.L().Warn("Backfill interrupted", zap.Int64("id:", job.ID), zap.Int64("cursor:", job.Cursor))
+		if finishErr := b.finish(job, common.BackfillPending, nil); finishErr != nil {
+			return finishErr
+		}
+		return err
+	}
+	if finishErr := b.finish(job, common.BackfillFailed, err); finishErr != nil {
+		return finishErr
+	}
+	return err
+}
+
+// finish сохраняет итоговый статус задачи. Контекст вызова не используется,
+// чтобы статус был сохранён и после отмены.
+func (b *Backfiller) finish(job *models.BackfillJob, status string, cause error) error {
+	message := common.Empty
+	if cause != nil {
+		message = cause.Error()
+	}
+	job.Status = status
+	job.Error = message
+	return b.backfillRepository.FinishBackfill(context.Background(), job.ID, status, message)
+}
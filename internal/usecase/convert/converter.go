@@ -0,0 +1,186 @@
+package convert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// Converter пересчитывает суммы между монетами и валютами по ценам из currency_prices.
+type Converter struct {
+	majorRepository interfaces.MajorRepositoryI
+}
+
+// NewConverter создаёт новый конвертер.
+//
+// Параметры:
+//   - majorRepository: репозиторий, из которого берутся ближайшие к метке времени цены
+//
+// Возвращает указатель на Converter.
+func NewConverter(majorRepository interfaces.MajorRepositoryI) *Converter {
+	return &Converter{
+		majorRepository: majorRepository,
+	}
+}
+
+// Convert рассчитывает курс from -> to на момент req.Timestamp и пересчитывает req.Value.
+//
+// Стороны from и to могут быть ID монеты (bitcoin) или кодом валюты котировки (USD, EUR, BTC).
+// Маршрут ищется по графу котировок отслеживаемых монет (монета связана с валютами, в которых хранится её цена):
+//   - монета -> валюта: прямая цена монеты в валюте (direct), валюта -> монета: обратная цена (inverse);
+//   - иначе курс триангулируется (triangulated): монета -> монета через общую валюту котировки,
+//     валюта -> валюта через монету, котируемую в обеих валютах, монета -> валюта — через валюту и монету.
+//
+// Сначала пробуются короткие маршруты и маршруты через USD; если для маршрута нет нужной цены, пробуется следующий.
+// Для каждого звена берётся сэмпл, ближайший к метке времени. Курс с обратными звеньями округляется
+// до RatePrecision знаков после запятой.
+//
+// Возвращает ErrNoConversionRoute, если маршрута нет, ErrPriceNotFound, если ни для одного маршрута нет цен.
+func (cv *Converter) Convert(ctx context.Context, req *models.ConvertRequest) (*models.ConvertResponse, error) {
+	from, fromIsCurrency := side(req.From)
+	to, toIsCurrency := side(req.To)
+
+	response := &models.ConvertResponse{
+		From:      from,
+		To:        to,
+		Amount:    models.NewDecimal(req.Value),
+		Timestamp: req.Timestamp,
+		Samples:   make([]*models.PriceResponse, 0, common.MaxConversionLegs),
+	}
+
+	if from == to {
+		response.Route = common.RouteSame
+		response.Rate = models.NewDecimal(decimal.NewFromInt(1))
+		response.Result = models.NewDecimal(req.Value)
+		return response, nil
+	}
+
+	routes, err := cv.routes(ctx, from, fromIsCurrency, to, toIsCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := common.ErrNoConversionRoute
+	samples := make(map[leg]*models.PriceResponse)
+	for _, r := range routes {
+		rate, used, err := cv.follow(ctx, r, req.Timestamp, samples)
+		if errors.Is(err, common.ErrPriceNotFound) || errors.Is(err, common.ErrNoConversionRoute) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(r.legs) > 1:
+			response.Route = common.RouteTriangulated
+		case r.legs[0].inverse:
+			response.Route = common.RouteInverse
+		default:
+			response.Route = common.RouteDirect
+		}
+		response.Via = r.via
+		response.Rate = models.NewDecimal(rate)
+		response.Samples = append(response.Samples, used...)
+		response.Result = models.NewDecimal(req.Value.Mul(rate))
+		response.Skew = skew(response.Samples)
+		return response, nil
+	}
+	return nil, lastErr
+}
+
+// routes строит граф котировок и возвращает маршруты from -> to в порядке предпочтения.
+// Прямая пара монеты и валюты, а также цены монет в валюте по умолчанию пробуются, даже если пара не отслеживается:
+// её история может быть сохранена ранее.
+func (cv *Converter) routes(ctx context.Context, from string, fromIsCurrency bool, to string, toIsCurrency bool) ([]*route, error) {
+	watched, err := cv.majorRepository.WatchedCoins(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := newQuoteGraph(watched)
+	switch {
+	case !fromIsCurrency && toIsCurrency:
+		graph.link(from, to)
+	case fromIsCurrency && !toIsCurrency:
+		graph.link(to, from)
+	}
+	if !fromIsCurrency {
+		graph.link(from, common.DefaultCurrency)
+	}
+	if !toIsCurrency {
+		graph.link(to, common.DefaultCurrency)
+	}
+	return graph.routes(from, to, fromIsCurrency, common.MaxConversionLegs, common.MaxConversionRoutes), nil
+}
+
+// follow рассчитывает курс по маршруту. Сэмплы кэшируются в samples между маршрутами одного запроса.
+func (cv *Converter) follow(ctx context.Context, r *route, timestamp int64, samples map[leg]*models.PriceResponse) (decimal.Decimal, []*models.PriceResponse, error) {
+	numerator, denominator := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	inverse := false
+	used := make([]*models.PriceResponse, 0, len(r.legs))
+	for _, l := range r.legs {
+		key := leg{coin: l.coin, currency: l.currency}
+		sample, ok := samples[key]
+		if !ok {
+			var err error
+			if sample, err = cv.sample(ctx, l.coin, l.currency, timestamp); err != nil {
+				return decimal.Zero, nil, err
+			}
+			samples[key] = sample
+		}
+		used = append(used, sample)
+
+		if !l.inverse {
+			numerator = numerator.Mul(sample.Price.Decimal)
+			continue
+		}
+		if sample.Price.IsZero() {
+			return decimal.Zero, nil, common.ErrNoConversionRoute
+		}
+		denominator = denominator.Mul(sample.Price.Decimal)
+		inverse = true
+	}
+
+	if !inverse {
+		return numerator, used, nil
+	}
+	return numerator.DivRound(denominator, common.RatePrecision), used, nil
+}
+
+// sample возвращает ближайшую к метке времени цену монеты в валюте.
+func (cv *Converter) sample(ctx context.Context, coin, currency string, timestamp int64) (*models.PriceResponse, error) {
+	return cv.majorRepository.GetPrice(ctx, &models.PriceRequest{
+		Coin:      coin,
+		Timestamp: timestamp,
+		Currency:  currency,
+	})
+}
+
+// side нормализует сторону конвертации и сообщает, является ли она валютой котировки.
+func side(value string) (string, bool) {
+	if currency, err := common.NormalizeCurrency(value); err == nil && value != common.Empty {
+		return currency, true
+	}
+	return strings.ToLower(strings.TrimSpace(value)), false
+}
+
+// skew возвращает разницу между самым ранним и самым поздним сэмплом.
+func skew(samples []*models.PriceResponse) int64 {
+	if len(samples) == common.Zero {
+		return common.Zero
+	}
+
+	earliest, latest := samples[0].Timestamp, samples[0].Timestamp
+	for _, sample := range samples[1:] {
+		earliest = min(earliest, sample.Timestamp)
+		latest = max(latest, sample.Timestamp)
+	}
+	return latest - earliest
+}
@@ -0,0 +1,118 @@
+package convert
+
+import (
+	"slices"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+)
+
+// leg — одно звено маршрута: цена монеты coin в валюте currency, прямая или обратная.
+type leg struct {
+	coin     string
+	currency string
+	inverse  bool
+}
+
+// route — маршрут пересчёта: звенья и промежуточные узлы между from и to.
+type route struct {
+	legs []*leg
+	via  []string
+}
+
+// quoteGraph — двудольный граф котировок: монета связана с каждой валютой, в которой хранится её цена.
+type quoteGraph map[string][]string
+
+// newQuoteGraph строит граф по отслеживаемым монетам и их валютам.
+func newQuoteGraph(watched []*models.WatchedCoin) quoteGraph {
+	graph := make(quoteGraph, len(watched))
+	for _, coin := range watched {
+		for _, currency := range coin.Currencies {
+			graph.link(coin.Symbol, currency)
+		}
+	}
+	return graph
+}
+
+// link связывает монету с валютой котировки.
+func (g quoteGraph) link(coin, currency string) {
+	if !slices.Contains(g[coin], currency) {
+		g[coin] = append(g[coin], currency)
+	}
+	if !slices.Contains(g[currency], coin) {
+		g[currency] = append(g[currency], coin)
+	}
+}
+
+// routes возвращает не больше limit маршрутов from -> to длиной не больше maxLegs звеньев.
+//
+// Сначала идут короткие маршруты; при равной длине предпочитаются маршруты через валюту по умолчанию,
+// затем узлы перебираются по алфавиту. Узлы не повторяются внутри маршрута.
+func (g quoteGraph) routes(from, to string, fromIsCurrency bool, maxLegs, limit int) []*route {
+	for _, neighbours := range g {
+		slices.SortFunc(neighbours, preferNode)
+	}
+
+	var result []*route
+	path := []string{from}
+	var walk func(depth int)
+	walk = func(depth int) {
+		last := path[len(path)-1]
+		if len(path)-1 == depth {
+			if last == to {
+				result = append(result, newRoute(path, fromIsCurrency))
+			}
+			return
+		}
+		for _, next := range g[last] {
+			if len(result) >= limit {
+				return
+			}
+			if slices.Contains(path, next) || (next == to) != (len(path) == depth) {
+				continue
+			}
+			path = append(path, next)
+			walk(depth)
+			path = path[:len(path)-1]
+		}
+	}
+
+	for depth := 1; depth <= maxLegs && len(result) < limit; depth++ {
+		walk(depth)
+	}
+	return result
+}
+
+// newRoute превращает путь по графу в звенья. Узлы пути чередуются: монета, валюта, монета...
+// или валюта, монета, валюта...; звено от валюты к монете берётся по обратной цене.
+func newRoute(path []string, fromIsCurrency bool) *route {
+	r := &route{
+		legs: make([]*leg, 0, len(path)-1),
+		via:  slices.Clone(path[1 : len(path)-1]),
+	}
+	isCurrency := fromIsCurrency
+	for i := 1; i < len(path); i++ {
+		if isCurrency {
+			r.legs = append(r.legs, &leg{coin: path[i], currency: path[i-1], inverse: true})
+		} else {
+			r.legs = append(r.legs, &leg{coin: path[i-1], currency: path[i]})
+		}
+		isCurrency = !isCurrency
+	}
+	return r
+}
+
+// preferNode упорядочивает узлы: сначала валюта по умолчанию, затем по алфавиту.
+func preferNode(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == common.DefaultCurrency:
+		return -1
+	case b == common.DefaultCurrency:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,130 @@
+package gaps
+
+import (
+	"context"
+	"testYTask/internal/common"
+	"testYTask/internal/config"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Detector ищет пропуски в истории цен отслеживаемых монет и при необходимости
+// ставит задачи дозагрузки истории для их заполнения.
+type Detector struct {
+	cfg           *config.GapsConf
+	gapRepository interfaces.GapRepositoryI
+	backfiller    interfaces.BackfillerI
+}
+
+// NewDetector создаёт новый поиск пропусков.
+//
+// Параметры:
+//   - cfg: настройки поиска; если не заданы, поиск выключен
+//   - gapRepository: репозиторий найденных пропусков
+//   - backfiller: сервис дозагрузки истории для заполнения пропусков
+//
+// Возвращает указатель на Detector.
+func NewDetector(cfg *config.GapsConf, gapRepository interfaces.GapRepositoryI, backfiller interfaces.BackfillerI) *Detector {
+	if cfg == nil {
+		cfg = new(config.GapsConf)
+	}
+	return &Detector{
+		cfg:           cfg,
+		gapRepository: gapRepository,
+		backfiller:    backfiller,
+	}
+}
+
+// Run обновляет статусы пропусков в ремонте, ищет новые пропуски за LOOKBACK_SEC
+// и, если включён AUTO_REPAIR, ставит задачи дозагрузки для открытых пропусков.
+// Неустранимые пропуски (unrepairable) автоматически повторно не ремонтируются.
+func (d *Detector) Run() {
+	if !d.cfg.Enabled {
+		return
+	}
+
+	zap.L().Info("Starting gap detector")
+
+	ctx := context.Background()
+
+	threshold := d.cfg.ThresholdSec
+	if threshold <= common.Zero {
+		threshold = common.DefaultGapThreshold
+	}
+
+	// Дозагруженная история не может быть чаще точек источника, поэтому ремонт проверяется по большему из порогов.
+	if synced, err := d.gapRepository.SyncGapStatuses(ctx, max(threshold, d.backfiller.HistoryStep())); err != nil {
+		zap.L().Error("SyncGapStatuses failed", zap.Error(err))
+	} else if synced > common.Zero {
+		zap.L().Info("Price gap statuses synced", zap.Int64("count:", synced))
+	}
+	lookback := d.cfg.LookbackSec
+	if lookback <= common.Zero {
+		lookback = common.DefaultGapLookback
+	}
+
+	gaps, err := d.gapRepository.DetectGaps(ctx, time.Now().Unix()-lookback, threshold)
+	if err != nil {
+		zap.L().Error("DetectGaps failed", zap.Error(err))
+		return
+	}
+	for _, gap := range gaps {
+		zap.L().Warn("Price gap detected", zap.String("name:", gap.Coin), zap.String("currency:", gap.Currency),
+			zap.Int64("start:", gap.Start), zap.Int64("end:", gap.End), zap.Int64("duration:", gap.Duration))
+	}
+
+	if !d.cfg.AutoRepair {
+		return
+	}
+
+	open, err := d.gapRepository.ListGaps(ctx, &models.GapRequest{
+		Status: common.GapOpen,
+		Limit:  common.MaxGapRepairsPerRun,
+	})
+	if err != nil {
+		zap.L().Error("ListGaps failed", zap.Error(err))
+		return
+	}
+	for _, gap := range open {
+		if _, err := d.repair(ctx, gap); err != nil {
+			zap.L().Error("Gap repair failed", zap.Error(err), zap.Int64("id:", gap.ID), zap.String("name:", gap.Coin))
+		}
+	}
+}
+
+func (d *Detector) List(ctx context.Context, req *models.GapRequest) ([]*models.PriceGap, error) {
+	return d.gapRepository.ListGaps(ctx, req)
+}
+
+// Repair ставит задачу дозагрузки истории для указанного пропуска, в том числе повторно для неустранимого.
+//
+// Возвращает ErrGapNotFound, если пропуска нет, и ErrGapNotRepairable, если он уже заполнен или заполняется.
+func (d *Detector) Repair(ctx context.Context, id int64) (*models.PriceGap, error) {
+	gap, err := d.gapRepository.GetGap(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if gap.Status != common.GapOpen && gap.Status != common.GapFailed && gap.Status != common.GapUnrepairable {
+		return nil, common.ErrGapNotRepairable
+	}
+	return d.repair(ctx, gap)
+}
+
+// repair создаёт задачу дозагрузки на интервал пропуска и привязывает её к пропуску.
+func (d *Detector) repair(ctx context.Context, gap *models.PriceGap) (*models.PriceGap, error) {
+	job, err := d.backfiller.Enqueue(ctx, &models.BackfillRequest{
+		Coin:     gap.Coin,
+		Currency: gap.Currency,
+		From:     gap.Start,
+		To:       gap.End,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Info("Gap repair queued", zap.Int64("id:", gap.ID), zap.Int64("backfill:", job.ID), zap.String("name:", gap.Coin))
+	return d.gapRepository.AttachBackfill(ctx, gap.ID, job.ID)
+}
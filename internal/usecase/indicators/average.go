@@ -0,0 +1,106 @@
+package indicators
+
+// sma рассчитывает простое скользящее среднее за period значений.
+// Сумма окна обновляется инкрементально и пересчитывается заново при каждом обороте буфера,
+// чтобы ошибка округления не накапливалась.
+type sma struct {
+	window *ring
+	sum    float64
+}
+
+func newSMA(period int) *sma {
+	return &sma{window: newRing(period)}
+}
+
+// next добавляет значение и возвращает среднее, когда окно заполнено.
+func (s *sma) next(value float64) (float64, bool) {
+	old, evicted := s.window.push(value)
+	s.sum += value
+	if evicted {
+		s.sum -= old
+	}
+	if !s.window.full {
+		return 0, false
+	}
+	if s.window.next == 0 {
+		s.sum = s.window.mean() * float64(len(s.window.values))
+	}
+	return s.sum / float64(len(s.window.values)), true
+}
+
+// ema рассчитывает экспоненциальное скользящее среднее с коэффициентом alpha.
+// Начальное значение — простое среднее первых period значений.
+type ema struct {
+	alpha float64
+	seed  *sma
+	value float64
+	ready bool
+}
+
+func newEMA(period int, alpha float64) *ema {
+	return &ema{alpha: alpha, seed: newSMA(period)}
+}
+
+// next добавляет значение и возвращает среднее, начиная с period-го значения.
+func (e *ema) next(value float64) (float64, bool) {
+	if e.ready {
+		e.value += e.alpha * (value - e.value)
+		return e.value, true
+	}
+	seed, ok := e.seed.next(value)
+	if !ok {
+		return 0, false
+	}
+	e.value, e.ready, e.seed = seed, true, nil
+	return e.value, true
+}
+
+// smaIndicator — индикатор SMA.
+type smaIndicator struct {
+	period int
+	sma    *sma
+}
+
+func newSMAIndicator(period int) *smaIndicator {
+	return &smaIndicator{period: period, sma: newSMA(period)}
+}
+
+func (i *smaIndicator) Columns() []string {
+	return []string{column(NameSMA, float64(i.period))}
+}
+
+func (i *smaIndicator) Warmup() int {
+	return i.period
+}
+
+func (i *smaIndicator) Update(value float64) []float64 {
+	if result, ok := i.sma.next(value); ok {
+		return []float64{result}
+	}
+	return nil
+}
+
+// emaIndicator — индикатор EMA с коэффициентом 2 / (period + 1).
+type emaIndicator struct {
+	period int
+	ema    *ema
+}
+
+func newEMAIndicator(period int) *emaIndicator {
+	return &emaIndicator{period: period, ema: newEMA(period, 2/float64(period+1))}
+}
+
+func (i *emaIndicator) Columns() []string {
+	return []string{column(NameEMA, float64(i.period))}
+}
+
+func (i *emaIndicator) Warmup() int {
+	return i.period
+}
+
+func (i *emaIndicator) Update(value float64) []float64 {
+	if result, ok := i.ema.next(value); ok {
+		return []float64{result}
+	}
+	return nil
+}
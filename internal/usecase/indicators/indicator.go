@@ -0,0 +1,183 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testYTask/internal/common"
+)
+
+// Indicator — потоковый технический индикатор.
+//
+// Значения подаются по одному в порядке времени, состояние занимает O(period) памяти,
+// поэтому один и тот же код подходит и для расчёта по истории, и для расчёта по новым ценам.
+type Indicator interface {
+	// Columns возвращает имена выходных значений, например ["sma_20"] или ["macd_12_26_9", "macd_12_26_9_signal", "macd_12_26_9_hist"].
+	Columns() []string
+	// Warmup возвращает число значений, после которого Update начинает возвращать результат.
+	Warmup() int
+	// Update добавляет значение и возвращает выходные значения в порядке Columns или nil, пока индикатор не прогрет.
+	Update(value float64) []float64
+}
+
+// Имена индикаторов в запросе.
+const (
+	NameSMA        = "sma"
+	NameEMA        = "ema"
+	NameRSI        = "rsi"
+	NameMACD       = "macd"
+	NameBollinger  = "bb"
+	NameVolatility = "vol"
+)
+
+// DefaultSpecs — индикаторы с параметрами по умолчанию, если в запросе они не указаны.
+var DefaultSpecs = []string{NameSMA, NameEMA, NameRSI, NameMACD, NameBollinger, NameVolatility}
+
+// aliases — полные имена индикаторов, принимаемые наравне с короткими.
+var aliases = map[string]string{
+	"bollinger":  NameBollinger,
+	"volatility": NameVolatility,
+}
+
+// New создаёт индикатор по описанию вида имя[:параметр[:параметр...]].
+//
+// Поддерживаются:
+//   - sma[:period] — простое скользящее среднее (по умолчанию 20);
+//   - ema[:period] — экспоненциальное скользящее среднее, начальное значение — SMA первых period значений (20);
+//   - rsi[:period] — индекс относительной силы со сглаживанием Уайлдера (14);
+//   - macd[:fast:slow:signal] — MACD, сигнальная линия и гистограмма (12:26:9);
+//   - bb[:period:k] — полосы Боллинджера: SMA ± k стандартных отклонений генеральной совокупности (20:2);
+//   - vol[:period] — реализованная волатильность: выборочное стандартное отклонение логарифмических доходностей
+//     за period интервалов, приведённое к году (20).
+//
+// stepSeconds — длительность интервала между значениями, используется для приведения волатильности к году.
+//
+// Возвращает ошибку, оборачивающую ErrInvalidIndicator, для неизвестного имени или недопустимых параметров.
+func New(spec string, stepSeconds int64) (Indicator, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(spec)), ":")
+	name := parts[0]
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	params := parts[1:]
+
+	switch name {
+	case NameSMA, NameEMA, NameRSI, NameVolatility:
+		defaults := map[string]int{NameSMA: 20, NameEMA: 20, NameRSI: 14, NameVolatility: 20}
+		values, err := periods(spec, params, []int{defaults[name]})
+		if err != nil {
+			return nil, err
+		}
+		period := values[0]
+		switch name {
+		case NameSMA:
+			return newSMAIndicator(period), nil
+		case NameEMA:
+			return newEMAIndicator(period), nil
+		case NameRSI:
+			return newRSI(period), nil
+		default:
+			if period < 2 {
+				return nil, fmt.Errorf("%w: %s: period must be at least 2", common.ErrInvalidIndicator, spec)
+			}
+			return newVolatility(period, stepSeconds), nil
+		}
+	case NameMACD:
+		values, err := periods(spec, params, []int{12, 26, 9})
+		if err != nil {
+			return nil, err
+		}
+		if values[0] >= values[1] {
+			return nil, fmt.Errorf("%w: %s: fast period must be less than slow", common.ErrInvalidIndicator, spec)
+		}
+		return newMACD(values[0], values[1], values[2]), nil
+	case NameBollinger:
+		if len(params) > 2 {
+			return nil, fmt.Errorf("%w: %s: too many parameters", common.ErrInvalidIndicator, spec)
+		}
+		k := 2.0
+		if len(params) == 2 {
+			value, err := strconv.ParseFloat(params[1], 64)
+			if err != nil || value <= common.Zero || value > common.MaxBollingerWidth {
+				return nil, fmt.Errorf("%w: %s: width must be in (0, %d]", common.ErrInvalidIndicator, spec, common.MaxBollingerWidth)
+			}
+			k = value
+			params = params[:1]
+		}
+		values, err := periods(spec, params, []int{20})
+		if err != nil {
+			return nil, err
+		}
+		return newBollinger(values[0], k), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown indicator %q", common.ErrInvalidIndicator, parts[0])
+	}
+}
+
+// periods разбирает целочисленные параметры индикатора; отсутствующие берутся из defaults.
+func periods(spec string, params []string, defaults []int) ([]int, error) {
+	if len(params) > len(defaults) {
+		return nil, fmt.Errorf("%w: %s: too many parameters", common.ErrInvalidIndicator, spec)
+	}
+
+	values := append([]int(nil), defaults...)
+	for i, param := range params {
+		value, err := strconv.Atoi(param)
+		if err != nil || value < 1 || value > common.MaxIndicatorPeriod {
+			return nil, fmt.Errorf("%w: %s: period must be in [1, %d]", common.ErrInvalidIndicator, spec, common.MaxIndicatorPeriod)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// column формирует имя выходного значения из имени индикатора и его параметров, например "bb_20_2".
+func column(name string, params ...float64) string {
+	parts := make([]string, 0, len(params)+1)
+	parts = append(parts, name)
+	for _, param := range params {
+		parts = append(parts, strconv.FormatFloat(param, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
+
+// ring — кольцевой буфер последних size значений.
+type ring struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newRing(size int) *ring {
+	return &ring{values: make([]float64, size)}
+}
+
+// push добавляет значение и возвращает вытесненное, если буфер был заполнен.
+func (r *ring) push(value float64) (float64, bool) {
+	old, evicted := r.values[r.next], r.full
+	r.values[r.next] = value
+	r.next++
+	if r.next == len(r.values) {
+		r.next = 0
+		r.full = true
+	}
+	return old, evicted
+}
+
+// mean возвращает среднее значений буфера.
+func (r *ring) mean() float64 {
+	var sum float64
+	for _, value := range r.values {
+		sum += value
+	}
+	return sum / float64(len(r.values))
+}
+
+// variance возвращает дисперсию значений буфера относительно mean с делителем len - ddof.
+func (r *ring) variance(mean float64, ddof int) float64 {
+	var sum float64
+	for _, value := range r.values {
+		sum += (value - mean) * (value - mean)
+	}
+	return sum / float64(len(r.values)-ddof)
+}
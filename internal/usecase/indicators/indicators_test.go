@@ -0,0 +1,249 @@
+package indicators
+
+import (
+	"context"
+	"math"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// Цены закрытия из примеров StockCharts ChartSchool: расчёт EMA (10 дней) и RSI (14 дней).
+var (
+	emaCloses = []float64{
+		22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+		22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+		23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+	}
+	rsiCloses = []float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+		45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+		46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+		43.42, 42.66, 43.13,
+	}
+	// macdCloses продолжает rsiCloses, чтобы прогреть MACD 12/26/9 и получить несколько значений.
+	macdCloses = append(append([]float64(nil), rsiCloses...), 43.50, 43.90, 44.20, 44.80, 45.10, 44.60, 44.95)
+)
+
+// run подаёт значения в индикатор и возвращает выходные значения после прогрева.
+func run(t *testing.T, indicator Indicator, closes []float64) [][]float64 {
+	t.Helper()
+	var results [][]float64
+	for i, value := range closes {
+		result := indicator.Update(value)
+		if (result != nil) != (i+1 >= indicator.Warmup()) {
+			t.Fatalf("value %d: got %v, warmup is %d", i, result, indicator.Warmup())
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func TestIndicators(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		step      int64
+		closes    []float64
+		columns   []string
+		want      [][]float64
+		tolerance float64
+	}{
+		{
+			name:    "sma 10",
+			spec:    "sma:10",
+			closes:  emaCloses,
+			columns: []string{"sma_10"},
+			want: [][]float64{
+				{22.22}, {22.21}, {22.23}, {22.26}, {22.30}, {22.42}, {22.61}, {22.77}, {22.91}, {23.08}, {23.21},
+				{23.38}, {23.52}, {23.65}, {23.71}, {23.68}, {23.61}, {23.51}, {23.43}, {23.28}, {23.13},
+			},
+			tolerance: 0.01,
+		},
+		{
+			name:    "ema 10",
+			spec:    "ema:10",
+			closes:  emaCloses,
+			columns: []string{"ema_10"},
+			want: [][]float64{
+				{22.22}, {22.21}, {22.24}, {22.27}, {22.33}, {22.52}, {22.80}, {22.97}, {23.13}, {23.28}, {23.34},
+				{23.43}, {23.51}, {23.54}, {23.47}, {23.40}, {23.39}, {23.26}, {23.23}, {23.08}, {22.92},
+			},
+			tolerance: 0.01,
+		},
+		{
+			// Опубликованные значения посчитаны по округлённым средним, отсюда допуск 0.1.
+			name:    "wilder rsi 14",
+			spec:    "rsi",
+			closes:  rsiCloses,
+			columns: []string{"rsi_14"},
+			want: [][]float64{
+				{70.53}, {66.32}, {66.55}, {69.41}, {66.36}, {57.97}, {62.93}, {63.26}, {56.06}, {62.38},
+				{54.71}, {50.42}, {39.99}, {41.46}, {41.87}, {45.46}, {37.30}, {33.08}, {37.77},
+			},
+			tolerance: 0.1,
+		},
+		{
+			name:    "macd 12 26 9",
+			spec:    "macd",
+			closes:  macdCloses,
+			columns: []string{"macd_12_26_9", "macd_12_26_9_signal", "macd_12_26_9_hist"},
+			want: [][]float64{
+				{-0.502083, -0.148441, -0.353642},
+				{-0.485916, -0.215936, -0.269981},
+				{-0.443781, -0.261505, -0.182276},
+				{-0.357848, -0.280773, -0.077075},
+				{-0.262512, -0.277121, 0.014609},
+				{-0.224713, -0.266640, 0.041926},
+				{-0.164618, -0.246235, 0.081617},
+			},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "bollinger 20 2",
+			spec:      "bb",
+			closes:    rsiCloses[:21],
+			columns:   []string{"bb_20_2_middle", "bb_20_2_upper", "bb_20_2_lower"},
+			want:      [][]float64{{45.409, 47.115328, 43.702672}, {45.5025, 47.168740, 43.836260}},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "bollinger 5 2",
+			spec:      "bollinger:5:2",
+			closes:    rsiCloses[:5],
+			columns:   []string{"bb_5_2_middle", "bb_5_2_upper", "bb_5_2_lower"},
+			want:      [][]float64{{44.104, 44.635504, 43.572496}},
+			tolerance: 1e-6,
+		},
+		{
+			name:      "daily volatility 5",
+			spec:      "vol:5",
+			step:      86400,
+			closes:    rsiCloses[:8],
+			columns:   []string{"vol_5"},
+			want:      [][]float64{{0.225052}, {0.209437}, {0.207221}},
+			tolerance: 1e-6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := tt.step
+			if step == 0 {
+				step = 3600
+			}
+			indicator, err := New(tt.spec, step)
+			if err != nil {
+				t.Fatalf("New(%q): %v", tt.spec, err)
+			}
+			columns := indicator.Columns()
+			if len(columns) != len(tt.columns) {
+				t.Fatalf("columns: got %v, want %v", columns, tt.columns)
+			}
+			for i := range columns {
+				if columns[i] != tt.columns[i] {
+					t.Fatalf("columns: got %v, want %v", columns, tt.columns)
+				}
+			}
+
+			got := run(t, indicator, tt.closes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d values, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				for j := range tt.want[i] {
+					if math.Abs(got[i][j]-tt.want[i][j]) > tt.tolerance {
+						t.Errorf("value %d %s: got %.6f, want %.6f", i, columns[j], got[i][j], tt.want[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMACDHistogram(t *testing.T) {
+	indicator, err := New("macd:3:6:4", 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range run(t, indicator, rsiCloses) {
+		if math.Abs(result[2]-(result[0]-result[1])) > 1e-12 {
+			t.Errorf("hist %v != macd - signal %v", result[2], result[0]-result[1])
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, spec := range []string{"foo", "sma:0", "sma:1:2", "macd:26:12", "bb:20:0", "vol:1", "ema:x"} {
+		if _, err := New(spec, 3600); err == nil {
+			t.Errorf("New(%q): expected error", spec)
+		}
+	}
+}
+
+// candleRepository отдаёт заданные свечи; остальные методы репозитория в тестах не вызываются.
+type candleRepository struct {
+	interfaces.MajorRepositoryI
+	candles []*models.Candle
+}
+
+func (r *candleRepository) GetCandles(_ context.Context, req *models.CandleRequest) (*models.CandleResponse, error) {
+	return &models.CandleResponse{Coin: req.Coin, Currency: req.Currency, Interval: req.Step, Candles: r.candles}, nil
+}
+
+func TestComputeSkipsFilledCloses(t *testing.T) {
+	closes := []*float64{ptr(10), ptr(11), nil, nil, ptr(12), ptr(13)}
+	candles := make([]*models.Candle, 0, len(closes))
+	for i, value := range closes {
+		candle := &models.Candle{Timestamp: int64(i) * 60, Empty: value == nil}
+		if value != nil {
+			price := models.NewDecimal(decimal.NewFromFloat(*value))
+			candle.Close = &price
+		}
+		candles = append(candles, candle)
+	}
+
+	service := NewService(&candleRepository{candles: candles})
+	response, err := service.Compute(context.Background(), &models.IndicatorRequest{
+		Coin: "bitcoin", Currency: "USD", From: 0, To: 300, Step: 60, Indicators: "sma:2,vol:2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Перенесённые цены не участвуют в расчёте: sma_2 после пропуска — среднее 11 и 12, а не 11 и 11.
+	want := []*float64{nil, ptr(10.5), nil, nil, ptr(11.5), ptr(12.5)}
+	if len(response.Points) != len(want) {
+		t.Fatalf("got %d points, want %d", len(response.Points), len(want))
+	}
+	for i, point := range response.Points {
+		if point.Filled != (closes[i] == nil) {
+			t.Errorf("point %d: filled = %v", i, point.Filled)
+		}
+		got := point.Values["sma_2"]
+		if (got == nil) != (want[i] == nil) || (got != nil && math.Abs(*got-*want[i]) > 1e-9) {
+			t.Errorf("point %d: sma_2 = %v, want %v", i, deref(got), deref(want[i]))
+		}
+		if point.Filled && point.Values["vol_2"] != nil {
+			t.Errorf("point %d: vol_2 = %v for a filled close", i, *point.Values["vol_2"])
+		}
+	}
+	if response.Points[5].Values["vol_2"] == nil {
+		t.Error("vol_2 is not computed after the gap")
+	}
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}
+
+func deref(value *float64) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
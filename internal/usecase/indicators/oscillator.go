@@ -0,0 +1,89 @@
+package indicators
+
+// rsi рассчитывает индекс относительной силы по Уайлдеру: средние рост и падение сглаживаются
+// с коэффициентом 1 / period, начальные средние — простые средние за первые period изменений.
+type rsi struct {
+	period   int
+	gain     *ema
+	loss     *ema
+	previous float64
+	started  bool
+}
+
+func newRSI(period int) *rsi {
+	alpha := 1 / float64(period)
+	return &rsi{period: period, gain: newEMA(period, alpha), loss: newEMA(period, alpha)}
+}
+
+func (i *rsi) Columns() []string {
+	return []string{column(NameRSI, float64(i.period))}
+}
+
+func (i *rsi) Warmup() int {
+	return i.period + 1
+}
+
+// Update возвращает RSI от 0 до 100; без изменений цены за окно возвращается 50.
+func (i *rsi) Update(value float64) []float64 {
+	if !i.started {
+		i.previous, i.started = value, true
+		return nil
+	}
+	change := value - i.previous
+	i.previous = value
+
+	avgGain, ok := i.gain.next(max(change, 0))
+	avgLoss, _ := i.loss.next(max(-change, 0))
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case avgLoss == 0 && avgGain == 0:
+		return []float64{50}
+	case avgLoss == 0:
+		return []float64{100}
+	default:
+		return []float64{100 - 100/(1+avgGain/avgLoss)}
+	}
+}
+
+// macd рассчитывает разность быстрой и медленной EMA, сигнальную линию (EMA разности) и гистограмму.
+type macd struct {
+	fast, slow, signal          int
+	fastEMA, slowEMA, signalEMA *ema
+}
+
+func newMACD(fast, slow, signal int) *macd {
+	return &macd{
+		fast:      fast,
+		slow:      slow,
+		signal:    signal,
+		fastEMA:   newEMA(fast, 2/float64(fast+1)),
+		slowEMA:   newEMA(slow, 2/float64(slow+1)),
+		signalEMA: newEMA(signal, 2/float64(signal+1)),
+	}
+}
+
+func (i *macd) Columns() []string {
+	name := column(NameMACD, float64(i.fast), float64(i.slow), float64(i.signal))
+	return []string{name, name + "_signal", name + "_hist"}
+}
+
+func (i *macd) Warmup() int {
+	return i.slow + i.signal - 1
+}
+
+func (i *macd) Update(value float64) []float64 {
+	fast, _ := i.fastEMA.next(value)
+	slow, ok := i.slowEMA.next(value)
+	if !ok {
+		return nil
+	}
+	line := fast - slow
+	signal, ok := i.signalEMA.next(line)
+	if !ok {
+		return nil
+	}
+	return []float64{line, signal, line - signal}
+}
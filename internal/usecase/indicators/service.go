@@ -0,0 +1,152 @@
+package indicators
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+)
+
+// Service рассчитывает технические индикаторы по ценам закрытия свечей из currency_prices.
+//
+// Индикаторы считаются в float64: это статистические оценки, а не цены, и для них нужны
+// корень и логарифм, которых нет в точной десятичной арифметике. Цены закрытия возвращаются точными.
+type Service struct {
+	majorRepository interfaces.MajorRepositoryI
+}
+
+// NewService создаёт сервис технических индикаторов.
+//
+// Параметры:
+//   - majorRepository: репозиторий, из которого берутся свечи
+//
+// Возвращает указатель на Service.
+func NewService(majorRepository interfaces.MajorRepositoryI) *Service {
+	return &Service{
+		majorRepository: majorRepository,
+	}
+}
+
+// Compute рассчитывает индикаторы req.Indicators по свечам с интервалом req.Step за период [req.From, req.To].
+//
+// Перед началом периода дополнительно загружается история для прогрева индикаторов (см. IndicatorLookback).
+// Цена закрытия интервала без сэмплов переносится из предыдущего интервала и помечается Filled; такая цена
+// не подаётся в индикаторы, чтобы не вносить нулевые изменения цены, и значения индикаторов для неё равны nil.
+// Интервалы до первого сэмпла пропускаются.
+//
+// Возвращает ошибку, оборачивающую ErrInvalidIndicator, для некорректного списка индикаторов
+// и ErrInvalidRange, если вместе с прогревом запрошено больше MaxCandles свечей.
+func (s *Service) Compute(ctx context.Context, req *models.IndicatorRequest) (*models.IndicatorResponse, error) {
+	indicators, columns, err := build(req.Indicators, req.Step)
+	if err != nil {
+		return nil, err
+	}
+
+	warmup := 0
+	for _, indicator := range indicators {
+		warmup = max(warmup, indicator.Warmup())
+	}
+
+	// Начало периода выравнивается по границе интервала так же, как у свечей.
+	start := req.From - req.From%req.Step
+	lookbackFrom := max(start-int64(warmup*common.IndicatorLookback)*req.Step, 0)
+	if (req.To-lookbackFrom)/req.Step+1 > common.MaxCandles {
+		return nil, fmt.Errorf("%w: too many candles requested with warmup, maximum is %d", common.ErrInvalidRange, common.MaxCandles)
+	}
+
+	candles, err := s.majorRepository.GetCandles(ctx, &models.CandleRequest{
+		Coin:     req.Coin,
+		From:     lookbackFrom,
+		To:       req.To,
+		Currency: req.Currency,
+		Step:     req.Step,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var last *models.Decimal
+
+	points := make([]*models.IndicatorPoint, 0, (req.To-start)/req.Step+1)
+	for _, candle := range candles.Candles {
+		closePrice, filled := candle.Close, false
+		if closePrice == nil {
+			if last == nil {
+				continue
+			}
+			closePrice, filled = last, true
+		}
+		last = closePrice
+
+		point := &models.IndicatorPoint{
+			Timestamp: candle.Timestamp,
+			Close:     *closePrice,
+			Filled:    filled,
+			Values:    make(map[string]*float64, len(columns)),
+		}
+		value := closePrice.InexactFloat64()
+		for _, indicator := range indicators {
+			var result []float64
+			if !filled {
+				result = indicator.Update(value)
+			}
+			for i, name := range indicator.Columns() {
+				point.Values[name] = finite(result, i)
+			}
+		}
+		if candle.Timestamp >= start {
+			points = append(points, point)
+		}
+	}
+
+	return &models.IndicatorResponse{
+		Coin:     req.Coin,
+		Currency: req.Currency,
+		Interval: req.Step,
+		From:     start,
+		To:       req.To,
+		Columns:  columns,
+		Points:   points,
+	}, nil
+}
+
+// build создаёт индикаторы по списку описаний через запятую; повторяющиеся описания учитываются один раз.
+func build(list string, step int64) ([]Indicator, []string, error) {
+	specs := DefaultSpecs
+	if strings.TrimSpace(list) != common.Empty {
+		specs = strings.Split(list, ",")
+	}
+	if len(specs) > common.MaxIndicators {
+		return nil, nil, fmt.Errorf("%w: at most %d indicators per request", common.ErrInvalidIndicator, common.MaxIndicators)
+	}
+
+	seen := make(map[string]bool, len(specs))
+	indicators := make([]Indicator, 0, len(specs))
+	columns := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		indicator, err := New(spec, step)
+		if err != nil {
+			return nil, nil, err
+		}
+		names := indicator.Columns()
+		if seen[names[0]] {
+			continue
+		}
+		seen[names[0]] = true
+		indicators = append(indicators, indicator)
+		columns = append(columns, names...)
+	}
+	return indicators, columns, nil
+}
+
+// finite возвращает i-е значение результата или nil, если индикатор не прогрет или значение не конечно.
+func finite(result []float64, i int) *float64 {
+	if result == nil || math.IsNaN(result[i]) || math.IsInf(result[i], 0) {
+		return nil
+	}
+	value := result[i]
+	return &value
+}
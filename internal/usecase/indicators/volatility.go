@@ -0,0 +1,78 @@
+package indicators
+
+import "math"
+
+// secondsPerYear — длительность года для приведения волатильности: криптовалюты торгуются круглосуточно.
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// bollinger рассчитывает полосы Боллинджера: среднюю линию (SMA) и границы на k стандартных отклонений от неё.
+type bollinger struct {
+	period int
+	k      float64
+	window *ring
+}
+
+func newBollinger(period int, k float64) *bollinger {
+	return &bollinger{period: period, k: k, window: newRing(period)}
+}
+
+func (i *bollinger) Columns() []string {
+	name := column(NameBollinger, float64(i.period), i.k)
+	return []string{name + "_middle", name + "_upper", name + "_lower"}
+}
+
+func (i *bollinger) Warmup() int {
+	return i.period
+}
+
+func (i *bollinger) Update(value float64) []float64 {
+	i.window.push(value)
+	if !i.window.full {
+		return nil
+	}
+	middle := i.window.mean()
+	width := i.k * math.Sqrt(i.window.variance(middle, 0))
+	return []float64{middle, middle + width, middle - width}
+}
+
+// volatility рассчитывает реализованную волатильность: выборочное стандартное отклонение логарифмических
+// доходностей за period интервалов, умноженное на корень из числа интервалов в году. 0.45 означает 45% годовых.
+type volatility struct {
+	period   int
+	annual   float64
+	returns  *ring
+	previous float64
+	started  bool
+}
+
+func newVolatility(period int, stepSeconds int64) *volatility {
+	return &volatility{
+		period:  period,
+		annual:  math.Sqrt(secondsPerYear / float64(stepSeconds)),
+		returns: newRing(period),
+	}
+}
+
+func (i *volatility) Columns() []string {
+	return []string{column(NameVolatility, float64(i.period))}
+}
+
+func (i *volatility) Warmup() int {
+	return i.period + 1
+}
+
+// Update возвращает nil и для неположительных цен, для которых логарифмическая доходность не определена.
+func (i *volatility) Update(value float64) []float64 {
+	previous, started := i.previous, i.started
+	i.previous, i.started = value, true
+	if !started || previous <= 0 || value <= 0 {
+		return nil
+	}
+
+	i.returns.push(math.Log(value / previous))
+	if !i.returns.full {
+		return nil
+	}
+	mean := i.returns.mean()
+	return []float64{math.Sqrt(i.returns.variance(mean, 1)) * i.annual}
+}
@@ -0,0 +1,137 @@
+package job
+
+import (
+	"sort"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	madScale     = decimal.NewFromFloat(common.ConsensusMADScale)
+	madThreshold = decimal.NewFromFloat(common.ConsensusMADThreshold)
+	minDeviation = decimal.NewFromFloat(common.ConsensusMinDeviation)
+)
+
+// consensusPrice рассчитывает согласованную цену по котировкам нескольких источников.
+//
+// Котировка считается выбросом, если её отклонение от медианы превышает
+// ConsensusMADThreshold масштабированных медианных абсолютных отклонений (MAD)
+// и при этом больше ConsensusMinDeviation от медианы в относительном выражении.
+// Выбросы помечаются полем Outlier, цена считается как медиана остальных котировок.
+//
+// Возвращает ErrNoQuotes, если котировок нет.
+func consensusPrice(quotes []*models.ProviderQuote) (decimal.Decimal, error) {
+	if len(quotes) == common.Zero {
+		return decimal.Zero, common.ErrNoQuotes
+	}
+
+	prices := make([]decimal.Decimal, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Price
+	}
+	center := median(prices)
+
+	deviations := make([]decimal.Decimal, len(quotes))
+	for i, price := range prices {
+		deviations[i] = price.Sub(center).Abs()
+	}
+	mad := median(deviations).Mul(madScale)
+	relativeLimit := center.Abs().Mul(minDeviation)
+
+	inliers := make([]decimal.Decimal, 0, len(quotes))
+	for i, quote := range quotes {
+		quote.Outlier = false
+		if !center.IsZero() && deviations[i].GreaterThan(relativeLimit) &&
+			(mad.IsZero() || deviations[i].GreaterThan(mad.Mul(madThreshold))) {
+			quote.Outlier = true
+			continue
+		}
+		inliers = append(inliers, quote.Price)
+	}
+
+	if len(inliers) == common.Zero {
+		return center, nil
+	}
+	return median(inliers), nil
+}
+
+// consensusMarketData выбирает рыночные показатели для согласованной цены.
+//
+// Каждый показатель берётся из первой по приоритету котировки, которая не является выбросом
+// и сообщает этот показатель. Вызывается после consensusPrice, который размечает выбросы.
+func consensusMarketData(quotes []*models.ProviderQuote) models.MarketData {
+	var data models.MarketData
+	for _, quote := range quotes {
+		if quote.Outlier {
+			continue
+		}
+		if data.MarketCap == nil {
+			data.MarketCap = quote.MarketCap
+		}
+		if data.Volume24h == nil {
+			data.Volume24h = quote.Volume24h
+		}
+		if data.Change24h == nil {
+			data.Change24h = quote.Change24h
+		}
+	}
+	return data
+}
+
+// consensusSources возвращает котировки, из которых получена согласованная цена: центральную котировку
+// (или две центральные при чётном числе) среди не являющихся выбросами. Если выбросами помечены все котировки,
+// центральные выбираются среди всех. Вызывается после consensusPrice, который размечает выбросы.
+func consensusSources(quotes []*models.ProviderQuote) []*models.ProviderQuote {
+	inliers := make([]*models.ProviderQuote, 0, len(quotes))
+	for _, quote := range quotes {
+		if !quote.Outlier {
+			inliers = append(inliers, quote)
+		}
+	}
+	if len(inliers) == common.Zero {
+		inliers = append(inliers, quotes...)
+	}
+	if len(inliers) == common.Zero {
+		return nil
+	}
+	sort.SliceStable(inliers, func(i, j int) bool {
+		return inliers[i].Price.LessThan(inliers[j].Price)
+	})
+
+	middle := len(inliers) / 2
+	if len(inliers)%2 == common.Zero {
+		return inliers[middle-1 : middle+1]
+	}
+	return inliers[middle : middle+1]
+}
+
+// sampleTimestamp возвращает метку времени согласованной цены: самое позднее время обновления данных
+// среди источников, из которых получена цена (см. consensusSources) и которые его сообщают. Если ни один
+// из них время не сообщает, используется requestedAt — момент запроса. Время из будущего ограничивается requestedAt.
+func sampleTimestamp(quotes []*models.ProviderQuote, requestedAt int64) int64 {
+	var timestamp int64
+	for _, quote := range consensusSources(quotes) {
+		timestamp = max(timestamp, quote.UpdatedAt)
+	}
+	if timestamp <= common.Zero {
+		return requestedAt
+	}
+	return min(timestamp, requestedAt)
+}
+
+// median возвращает медиану значений, не изменяя исходный срез.
+// Для чётного числа значений возвращается точное среднее двух центральных.
+func median(values []decimal.Decimal) decimal.Decimal {
+	sorted := append([]decimal.Decimal(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == common.Zero {
+		return sorted[middle-1].Add(sorted[middle]).Div(decimal.NewFromInt(2))
+	}
+	return sorted[middle]
+}
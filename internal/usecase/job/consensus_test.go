@@ -0,0 +1,174 @@
+package job
+
+import (
+	"errors"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// quote создаёт котировку источника с ценой price и временем обновления updatedAt.
+func quote(provider, price string, updatedAt int64) *models.ProviderQuote {
+	return &models.ProviderQuote{
+		Provider:  provider,
+		Currency:  "USD",
+		Price:     decimal.RequireFromString(price),
+		UpdatedAt: updatedAt,
+	}
+}
+
+func TestConsensusPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   []string
+		want     string
+		outliers []bool
+	}{
+		{name: "single quote", prices: []string{"117150.25"}, want: "117150.25", outliers: []bool{false}},
+		{name: "even count is the exact mean of the middle pair", prices: []string{"1", "2"}, want: "1.5", outliers: []bool{false, false}},
+		{
+			name:     "outlier rejected by MAD",
+			prices:   []string{"100", "100.2", "99.9", "120"},
+			want:     "100",
+			outliers: []bool{false, false, false, true},
+		},
+		{
+			name:     "outlier rejected when MAD is zero",
+			prices:   []string{"100", "100", "100", "110"},
+			want:     "100",
+			outliers: []bool{false, false, false, true},
+		},
+		{
+			name:     "deviation under the relative minimum is kept",
+			prices:   []string{"100", "100", "100", "100.3"},
+			want:     "100",
+			outliers: []bool{false, false, false, false},
+		},
+		{
+			name:     "two diverging sources are both kept",
+			prices:   []string{"100", "200"},
+			want:     "150",
+			outliers: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes := make([]*models.ProviderQuote, 0, len(tt.prices))
+			for _, price := range tt.prices {
+				q := quote("provider", price, 0)
+				q.Outlier = true
+				quotes = append(quotes, q)
+			}
+
+			got, err := consensusPrice(quotes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			for i, q := range quotes {
+				if q.Outlier != tt.outliers[i] {
+					t.Errorf("quote %d (%s): outlier = %v, want %v", i, q.Price, q.Outlier, tt.outliers[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConsensusPriceNoQuotes(t *testing.T) {
+	if _, err := consensusPrice(nil); !errors.Is(err, common.ErrNoQuotes) {
+		t.Errorf("got %v, want ErrNoQuotes", err)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{name: "odd", values: []string{"3", "1", "2"}, want: "2"},
+		{name: "even", values: []string{"4", "1", "3", "2"}, want: "2.5"},
+		{name: "even with exact decimals", values: []string{"0.000000001", "0.000000002"}, want: "0.0000000015"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]decimal.Decimal, 0, len(tt.values))
+			for _, value := range tt.values {
+				values = append(values, decimal.RequireFromString(value))
+			}
+			if got := median(values); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if !values[0].Equal(decimal.RequireFromString(tt.values[0])) {
+				t.Error("input values were reordered")
+			}
+		})
+	}
+}
+
+func TestSampleTimestamp(t *testing.T) {
+	const requestedAt = 1754604000
+
+	tests := []struct {
+		name   string
+		quotes []*models.ProviderQuote
+		want   int64
+	}{
+		{
+			name:   "coingecko and binance",
+			quotes: []*models.ProviderQuote{quote("coingecko", "117150", 1754603950), quote("binance", "117151", 0)},
+			want:   1754603950,
+		},
+		{
+			name:   "no source reports update time",
+			quotes: []*models.ProviderQuote{quote("binance", "117151", 0), quote("kraken", "117149", 0)},
+			want:   requestedAt,
+		},
+		{
+			name:   "latest of reported times",
+			quotes: []*models.ProviderQuote{quote("coingecko", "117150", 1754603950), quote("coincap", "117152", 1754603980)},
+			want:   1754603980,
+		},
+		{
+			name: "time of an outlier is ignored",
+			quotes: []*models.ProviderQuote{
+				quote("coingecko", "117150", 1754603900),
+				quote("binance", "117151", 0),
+				quote("kraken", "117149", 0),
+				quote("coincap", "130000", 1754603990),
+			},
+			want: 1754603900,
+		},
+		{
+			name: "time of a source outside the median is ignored",
+			quotes: []*models.ProviderQuote{
+				quote("coingecko", "117140", 1754603900),
+				quote("binance", "117150", 0),
+				quote("kraken", "117160", 0),
+			},
+			want: requestedAt,
+		},
+		{
+			name:   "time from the future is capped",
+			quotes: []*models.ProviderQuote{quote("coingecko", "117150", requestedAt+60)},
+			want:   requestedAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := consensusPrice(tt.quotes); err != nil {
+				t.Fatal(err)
+			}
+			if got := sampleTimestamp(tt.quotes, requestedAt); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
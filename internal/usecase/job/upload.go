@@ -2,8 +2,11 @@ package job
 
 import (
 	"context"
+	"sort"
 	"testYTask/internal/common"
 	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -12,6 +15,7 @@ import (
 type UploadJob struct {
 	jobRepository  interfaces.JobRepositoryI
 	registryClient interfaces.RegistryClientI
+	listeners      []interfaces.PriceListenerI
 }
 
 // NewUploadJob создает новую задачу для запроса на сторонний источник и дальнейшим сохранением данных.
@@ -19,12 +23,14 @@ type UploadJob struct {
 // Параметры:
 //   - registryClient: клиент для работы с внешними HTTP-сервисами
 //   - jobRepository: репозиторий для работы с Postgres
+//   - listeners: получатели новых цен после их сохранения (например, поток цен для клиентов)
 //
 // Возвращает указатель на созданную задачу.
-func NewUploadJob(jobRepository interfaces.JobRepositoryI, registryClient interfaces.RegistryClientI) *UploadJob {
+func NewUploadJob(jobRepository interfaces.JobRepositoryI, registryClient interfaces.RegistryClientI, listeners ...interfaces.PriceListenerI) *UploadJob {
 	return &UploadJob{
 		jobRepository:  jobRepository,
 		registryClient: registryClient,
+		listeners:      listeners,
 	}
 }
 
@@ -40,19 +46,93 @@ func (job *UploadJob) Run() {
 		return
 	}
 
+	// Все монеты запрашиваются в объединённом наборе валют, сохраняются только отслеживаемые пары.
+	symbols := make([]string, 0, len(listCoins))
+	currencySet := make(map[string]bool)
 	for _, coin := range listCoins {
-
-		data, err := job.registryClient.CurrentData(ctx, coin)
-		if err != nil {
-			zap.L().Error("data retrieval error", zap.Error(err), zap.String("name:", coin))
-			continue
+		symbols = append(symbols, coin.Symbol)
+		for _, currency := range coin.Currencies {
+			currencySet[currency] = true
 		}
-		if err := job.jobRepository.CoinDataUpdate(ctx, coin, data, common.Precision, common.DefaultCurrency); err != nil {
-			zap.L().Error("update coin data error", zap.Error(err), zap.String("name:", coin))
-			continue
+	}
+	currencies := make([]string, 0, len(currencySet))
+	for currency := range currencySet {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	quotesByCoin, err := job.registryClient.CurrentDataMany(ctx, symbols, currencies)
+	if err != nil {
+		zap.L().Error("data retrieval error", zap.Error(err), zap.Int("coins:", len(listCoins)))
+		return
+	}
+
+	requestedAt := time.Now().Unix()
+
+	updates := make([]*models.CoinUpdate, 0, len(listCoins))
+	for _, coin := range listCoins {
+		for _, currency := range coin.Currencies {
+			quotes := make([]*models.ProviderQuote, 0)
+			for _, quote := range quotesByCoin[coin.Symbol] {
+				if quote.Currency == currency {
+					quotes = append(quotes, quote)
+				}
+			}
+			if len(quotes) == common.Zero {
+				zap.L().Error("data retrieval error", zap.Error(common.ErrNoQuotes), zap.String("name:", coin.Symbol), zap.String("currency:", currency))
+				continue
+			}
+
+			price, err := consensusPrice(quotes)
+			if err != nil {
+				zap.L().Error("consensus price error", zap.Error(err), zap.String("name:", coin.Symbol), zap.String("currency:", currency))
+				continue
+			}
+			for _, quote := range quotes {
+				if quote.Outlier {
+					zap.L().Warn("Provider quote rejected as outlier", zap.String("name:", coin.Symbol), zap.String("currency:", currency), zap.String("provider:", quote.Provider), zap.Stringer("price:", quote.Price), zap.Stringer("consensus:", price))
+				}
+			}
+
+			updates = append(updates, &models.CoinUpdate{
+				Coin:       coin.Symbol,
+				Currency:   currency,
+				Price:      price,
+				MarketData: consensusMarketData(quotes),
+				Timestamp:  sampleTimestamp(quotes, requestedAt),
+				Quotes:     quotes,
+			})
 		}
+	}
 
+	saved, err := job.jobRepository.CoinDataUpdate(ctx, updates)
+	if err != nil {
+		zap.L().Error("update coin data error", zap.Error(err), zap.Int("prices:", len(updates)))
+		return
 	}
 
+	zap.L().Info("Upload job saved prices", zap.Int("saved:", len(saved)), zap.Int("skipped:", len(updates)-len(saved)), zap.Int("watched:", len(listCoins)))
+
+	job.notify(saved)
 	zap.L().Info("Finished upload job")
 }
+
+// notify передаёт сохранённые цены всем получателям.
+func (job *UploadJob) notify(updates []*models.CoinUpdate) {
+	if len(job.listeners) == common.Zero || len(updates) == common.Zero {
+		return
+	}
+
+	events := make([]*models.PriceEvent, 0, len(updates))
+	for _, update := range updates {
+		events = append(events, &models.PriceEvent{
+			Coin:      update.Coin,
+			Currency:  update.Currency,
+			Price:     models.NewDecimal(update.Price),
+			Timestamp: update.Timestamp,
+		})
+	}
+	for _, listener := range job.listeners {
+		listener.Publish(events)
+	}
+}
@@ -0,0 +1,103 @@
+package market
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+)
+
+// snapshotWindows — окна изменения цены в снимке рынка.
+var snapshotWindows = []*models.MarketWindow{
+	{Name: "1h", Seconds: 60 * 60},
+	{Name: "24h", Seconds: 24 * 60 * 60},
+	{Name: "7d", Seconds: 7 * 24 * 60 * 60},
+}
+
+// Service строит снимок рынка и списки лидеров роста и падения по сохранённым ценам.
+type Service struct {
+	majorRepository interfaces.MajorRepositoryI
+}
+
+// NewService создаёт сервис обзора рынка.
+//
+// Параметры:
+//   - majorRepository: репозиторий, из которого берутся последние цены и опорные сэмплы
+//
+// Возвращает указатель на Service.
+func NewService(majorRepository interfaces.MajorRepositoryI) *Service {
+	return &Service{
+		majorRepository: majorRepository,
+	}
+}
+
+// Snapshot возвращает последние цены всех отслеживаемых пар в валюте currency (пустая строка — во всех валютах)
+// с изменением за 1h, 24h и 7d.
+func (s *Service) Snapshot(ctx context.Context, currency string) (*models.MarketSnapshot, error) {
+	tickers, err := s.majorRepository.MarketTickers(ctx, currency, snapshotWindows)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MarketSnapshot{
+		Currency:  currency,
+		Timestamp: time.Now().Unix(),
+		Items:     tickers,
+	}, nil
+}
+
+// Movers возвращает до limit монет с наибольшим ростом и до limit монет с наибольшим падением цены в валюте currency
+// за окно window. Монеты без опорного сэмпла и без изменения цены в списки не попадают.
+// При равном изменении монеты упорядочиваются по ID.
+func (s *Service) Movers(ctx context.Context, currency string, window *models.MarketWindow, limit int) (*models.MarketMovers, error) {
+	tickers, err := s.majorRepository.MarketTickers(ctx, currency, []*models.MarketWindow{window})
+	if err != nil {
+		return nil, err
+	}
+
+	movers := &models.MarketMovers{
+		Currency: currency,
+		Window:   window.Name,
+		Gainers:  make([]*models.MarketMover, 0),
+		Losers:   make([]*models.MarketMover, 0),
+	}
+	for _, ticker := range tickers {
+		change := ticker.Changes[window.Name]
+		if change == nil || change.Percent == nil {
+			continue
+		}
+		mover := &models.MarketMover{
+			Coin:               ticker.Coin,
+			Currency:           ticker.Currency,
+			Price:              ticker.Price,
+			Timestamp:          ticker.Timestamp,
+			ReferencePrice:     *change.ReferencePrice,
+			ReferenceTimestamp: *change.ReferenceTimestamp,
+			Change:             *change.Percent,
+		}
+		switch change.Percent.Sign() {
+		case 1:
+			movers.Gainers = append(movers.Gainers, mover)
+		case -1:
+			movers.Losers = append(movers.Losers, mover)
+		}
+	}
+
+	slices.SortFunc(movers.Gainers, func(a, b *models.MarketMover) int {
+		if result := b.Change.Cmp(a.Change.Decimal); result != common.Zero {
+			return result
+		}
+		return cmp.Compare(a.Coin, b.Coin)
+	})
+	slices.SortFunc(movers.Losers, func(a, b *models.MarketMover) int {
+		if result := a.Change.Cmp(b.Change.Decimal); result != common.Zero {
+			return result
+		}
+		return cmp.Compare(a.Coin, b.Coin)
+	})
+	movers.Gainers = movers.Gainers[:min(limit, len(movers.Gainers))]
+	movers.Losers = movers.Losers[:min(limit, len(movers.Losers))]
+	return movers, nil
+}
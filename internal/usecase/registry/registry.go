@@ -0,0 +1,225 @@
+package registry
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// snapshot — неизменяемая версия реестра. Обновление создаёт новую версию и атомарно
+// подменяет указатель, поэтому читатели не блокируются и всегда видят целый список.
+//
+// Ключи всех индексов — в нижнем регистре; ranked, symbols и names хранят монеты в порядке капитализации.
+type snapshot struct {
+	ranked    []*models.CoinInfo
+	coins     map[string]*models.CoinInfo
+	aliases   map[string]*models.CoinInfo
+	symbols   map[string][]*models.CoinInfo
+	names     map[string][]*models.CoinInfo
+	source    string
+	updatedAt time.Time
+}
+
+// Registry хранит список валидных монет и периодически обновляет его с источника.
+//
+// Монета определяется по ID, пользовательскому псевдониму, тикеру или названию (без учёта регистра)
+// именно в таком порядке приоритета. Каждый успешно загруженный список сохраняется в PostgreSQL; если при старте источник
+// недоступен, реестр восстанавливается из сохранённой копии. Неудачное обновление
+// оставляет текущий список без изменений.
+type Registry struct {
+	registryClient     interfaces.RegistryClientI
+	registryRepository interfaces.RegistryRepositoryI
+	aliases            map[string]string
+
+	current atomic.Pointer[snapshot]
+
+	mu          sync.Mutex
+	lastAttempt time.Time
+	lastError   string
+}
+
+// NewRegistry создаёт пустой реестр монет.
+//
+// Параметры:
+//   - registryClient: клиент источников, загружающий список монет
+//   - registryRepository: репозиторий сохранённой копии реестра
+//   - aliases: пользовательские псевдонимы монет (псевдоним -> ID), может быть nil
+//
+// Возвращает указатель на Registry.
+func NewRegistry(registryClient interfaces.RegistryClientI, registryRepository interfaces.RegistryRepositoryI, aliases map[string]string) *Registry {
+	r := &Registry{
+		registryClient:     registryClient,
+		registryRepository: registryRepository,
+		aliases:            make(map[string]string, len(aliases)),
+	}
+	for alias, id := range aliases {
+		r.aliases[normalize(alias)] = normalize(id)
+	}
+	r.swap(nil, common.Empty, time.Time{})
+	return r
+}
+
+// Init загружает реестр при старте: с источника, а при ошибке — из сохранённой копии.
+//
+// Возвращает ошибку, если реестр не удалось загрузить ни одним способом; реестр в этом
+// случае остаётся пустым до следующего успешного обновления.
+func (r *Registry) Init(ctx context.Context) error {
+	err := r.Refresh(ctx)
+	if err == nil {
+		return nil
+	}
+	zap.L().Warn("Coin registry refresh failed, restoring saved copy", zap.Error(err))
+
+	coins, updatedAt, rErr := r.registryRepository.LoadRegistry(ctx)
+	if rErr != nil {
+		return rErr
+	}
+	if len(coins) == common.Zero {
+		return common.ErrEmptyRegistry
+	}
+	r.swap(coins, common.RegistrySourceStorage, updatedAt)
+
+	zap.L().Info("Coin registry restored", zap.Int("coins:", len(coins)), zap.Time("saved_at:", updatedAt))
+	return nil
+}
+
+// Run обновляет реестр по расписанию.
+func (r *Registry) Run() {
+	if err := r.Refresh(context.Background()); err != nil {
+		zap.L().Error("Coin registry refresh failed", zap.Error(err))
+	}
+}
+
+// Refresh загружает список монет с источника, подменяет им реестр и сохраняет его копию.
+// Пустой список не применяется и возвращает ErrEmptyRegistry.
+func (r *Registry) Refresh(ctx context.Context) error {
+	coins, err := r.registryClient.LoadValidCoins(ctx)
+	if err == nil && len(coins) == common.Zero {
+		err = common.ErrEmptyRegistry
+	}
+	r.recordAttempt(err)
+	if err != nil {
+		return err
+	}
+
+	r.swap(coins, common.RegistrySourceAPI, time.Now())
+	zap.L().Info("Coin registry refreshed", zap.Int("coins:", len(coins)))
+
+	if err := r.registryRepository.SaveRegistry(ctx, coins); err != nil {
+		zap.L().Error("SaveRegistry failed", zap.Error(err))
+	}
+	return nil
+}
+
+// Resolve определяет монету по ID, псевдониму, тикеру или названию.
+//
+// Возвращает ErrCoinNotFound, если монета не найдена, и *AmbiguousCoinError, если тикер
+// или название соответствует нескольким монетам.
+func (r *Registry) Resolve(coin string) (*models.CoinInfo, error) {
+	current := r.current.Load()
+	query := normalize(coin)
+
+	if info, ok := current.coins[query]; ok {
+		return info, nil
+	}
+	if info, ok := current.aliases[query]; ok {
+		return info, nil
+	}
+	for _, index := range []map[string][]*models.CoinInfo{current.symbols, current.names} {
+		switch matches := index[query]; len(matches) {
+		case common.Zero:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return nil, &common.AmbiguousCoinError{Query: coin, Candidates: matches}
+		}
+	}
+	return nil, common.ErrCoinNotFound
+}
+
+// Stats возвращает размер реестра, источник и время текущего списка и результат последнего обновления.
+func (r *Registry) Stats() *models.RegistryStats {
+	current := r.current.Load()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &models.RegistryStats{
+		Coins:       len(current.coins),
+		Source:      current.source,
+		UpdatedAt:   current.updatedAt,
+		LastAttempt: r.lastAttempt,
+		LastError:   r.lastError,
+	}
+}
+
+// swap строит новую версию реестра с индексами и атомарно подменяет текущую.
+// Псевдонимы монет, отсутствующих в списке, пропускаются.
+func (r *Registry) swap(coins []*models.CoinInfo, source string, updatedAt time.Time) {
+	ranked := slices.Clone(coins)
+	slices.SortStableFunc(ranked, func(a, b *models.CoinInfo) int {
+		switch {
+		case a.MarketCapRank == nil && b.MarketCapRank == nil:
+			return common.Zero
+		case a.MarketCapRank == nil:
+			return 1
+		case b.MarketCapRank == nil:
+			return -1
+		default:
+			return cmp.Compare(*a.MarketCapRank, *b.MarketCapRank)
+		}
+	})
+
+	next := &snapshot{
+		ranked:    ranked,
+		coins:     make(map[string]*models.CoinInfo, len(ranked)),
+		aliases:   make(map[string]*models.CoinInfo, len(r.aliases)),
+		symbols:   make(map[string][]*models.CoinInfo, len(ranked)),
+		names:     make(map[string][]*models.CoinInfo, len(ranked)),
+		source:    source,
+		updatedAt: updatedAt,
+	}
+	for _, coin := range ranked {
+		next.coins[normalize(coin.ID)] = coin
+		if symbol := normalize(coin.Symbol); symbol != common.Empty {
+			next.symbols[symbol] = append(next.symbols[symbol], coin)
+		}
+		if name := normalize(coin.Name); name != common.Empty {
+			next.names[name] = append(next.names[name], coin)
+		}
+	}
+	for alias, id := range r.aliases {
+		if coin, ok := next.coins[id]; ok {
+			next.aliases[alias] = coin
+		} else if len(ranked) > common.Zero {
+			zap.L().Warn("Coin alias points to unknown coin", zap.String("alias:", alias), zap.String("name:", id))
+		}
+	}
+	r.current.Store(next)
+}
+
+// normalize приводит обозначение монеты к ключу индексов.
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func (r *Registry) recordAttempt(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastAttempt = time.Now()
+	r.lastError = common.Empty
+	if err != nil {
+		r.lastError = err.Error()
+	}
+}
@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/models"
+)
+
+const (
+	fieldID     = "id"
+	fieldAlias  = "alias"
+	fieldSymbol = "symbol"
+	fieldName   = "name"
+)
+
+// matchTiers задаёт порядок типов совпадений: меньшее значение — лучшее совпадение.
+var matchTiers = map[string]int{
+	common.MatchExact:     0,
+	common.MatchPrefix:    1,
+	common.MatchSubstring: 2,
+	common.MatchFuzzy:     3,
+}
+
+// searchHit — найденная монета, её лучшее совпадение и позиция в порядке капитализации.
+type searchHit struct {
+	match *models.CoinMatch
+	tier  int
+	rank  int
+}
+
+// Search ищет монеты по ID, псевдониму, тикеру и названию без учёта регистра.
+//
+// Для каждой монеты берётся лучшее совпадение: точное, по началу строки, по подстроке или
+// с опечатками в начале строки (одна правка для запросов от 3 символов, две — от 6).
+// Результаты упорядочены по типу совпадения, затем по рыночной капитализации.
+//
+// Параметры:
+//   - query: строка поиска
+//   - limit: наибольшее число результатов
+//
+// Возвращает найденные монеты; для пустого запроса — пустой список.
+func (r *Registry) Search(query string, limit int) []*models.CoinMatch {
+	current := r.current.Load()
+	query = normalize(query)
+
+	result := make([]*models.CoinMatch, 0)
+	if query == common.Empty || limit <= common.Zero {
+		return result
+	}
+
+	aliases := make(map[string][]string, len(current.aliases))
+	for alias, coin := range current.aliases {
+		aliases[coin.ID] = append(aliases[coin.ID], alias)
+	}
+
+	hits := make([]*searchHit, 0)
+	for rank, coin := range current.ranked {
+		fields := []struct{ name, value string }{
+			{fieldID, coin.ID},
+			{fieldSymbol, coin.Symbol},
+			{fieldName, coin.Name},
+		}
+		for _, alias := range aliases[coin.ID] {
+			fields = append(fields, struct{ name, value string }{fieldAlias, alias})
+		}
+
+		var best *searchHit
+		for _, field := range fields {
+			match, ok := matchValue(query, normalize(field.value))
+			if !ok || (best != nil && matchTiers[match] >= best.tier) {
+				continue
+			}
+			best = &searchHit{
+				match: &models.CoinMatch{CoinInfo: coin, MatchedOn: field.name, Match: match},
+				tier:  matchTiers[match],
+				rank:  rank,
+			}
+		}
+		if best != nil {
+			hits = append(hits, best)
+		}
+	}
+
+	slices.SortStableFunc(hits, func(a, b *searchHit) int {
+		if result := cmp.Compare(a.tier, b.tier); result != common.Zero {
+			return result
+		}
+		return cmp.Compare(a.rank, b.rank)
+	})
+
+	for _, hit := range hits[:min(limit, len(hits))] {
+		result = append(result, hit.match)
+	}
+	return result
+}
+
+// matchValue определяет тип совпадения запроса со значением поля.
+func matchValue(query, value string) (string, bool) {
+	switch {
+	case value == common.Empty:
+		return common.Empty, false
+	case value == query:
+		return common.MatchExact, true
+	case strings.HasPrefix(value, query):
+		return common.MatchPrefix, true
+	case strings.Contains(value, query):
+		return common.MatchSubstring, true
+	case prefixDistance(query, value) <= maxEdits(query):
+		return common.MatchFuzzy, true
+	default:
+		return common.Empty, false
+	}
+}
+
+// maxEdits возвращает допустимое число опечаток для запроса.
+func maxEdits(query string) int {
+	switch length := len([]rune(query)); {
+	case length < 3:
+		return -1
+	case length < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefixDistance возвращает наименьшее расстояние Левенштейна между query и началом value любой длины.
+func prefixDistance(query, value string) int {
+	q, v := []rune(query), []rune(value)
+
+	previous := make([]int, len(v)+1)
+	current := make([]int, len(v)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(q); i++ {
+		current[0] = i
+		for j := 1; j <= len(v); j++ {
+			cost := 1
+			if q[i-1] == v[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return slices.Min(previous)
+}
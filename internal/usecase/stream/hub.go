@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+	"testYTask/internal/common"
+	"testYTask/internal/domain/interfaces"
+	"testYTask/internal/domain/models"
+
+	"go.uber.org/zap"
+)
+
+// Hub рассылает новые цены подписанным соединениям.
+//
+// Цены одного запуска задачи загрузки передаются подписке одним пакетом, буфер подписки —
+// StreamBufferSize пакетов. Публикация не блокируется: если буфер заполнен, потребитель
+// считается медленным и отключается с ErrSlowConsumer, чтобы не задерживать задачу загрузки
+// и остальных подписчиков.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+
+	published atomic.Int64
+	delivered atomic.Int64
+	evicted   atomic.Int64
+}
+
+// NewHub создаёт новый хаб потока цен.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe регистрирует новую подписку с фильтром.
+//
+// Возвращает ErrTooManySubscribers, если достигнут лимит StreamMaxSubscribers.
+func (h *Hub) Subscribe(filter *models.StreamFilter) (interfaces.SubscriptionI, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscribers) >= common.StreamMaxSubscribers {
+		return nil, common.ErrTooManySubscribers
+	}
+
+	sub := newSubscription(filter, common.StreamBufferSize)
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe удаляет подписку и закрывает её канал. Повторный вызов безопасен.
+func (h *Hub) Unsubscribe(sub interfaces.SubscriptionI) {
+	s, ok := sub.(*Subscription)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.close(s, nil)
+}
+
+// Publish отправляет цены всем подписчикам, фильтр которых им соответствует.
+func (h *Hub) Publish(events []*models.PriceEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.published.Add(int64(len(events)))
+
+	for sub := range h.subscribers {
+		matched := make([]*models.PriceEvent, 0)
+		for _, event := range events {
+			if sub.matches(event) {
+				matched = append(matched, event)
+			}
+		}
+		if len(matched) == common.Zero {
+			continue
+		}
+
+		select {
+		case sub.events <- matched:
+			h.delivered.Add(int64(len(matched)))
+		default:
+			h.evicted.Add(1)
+			h.close(sub, common.ErrSlowConsumer)
+			zap.L().Warn("Stream subscriber evicted", zap.Error(common.ErrSlowConsumer), zap.Int("buffer:", cap(sub.events)))
+		}
+	}
+}
+
+// Stats возвращает число подписчиков и счётчики событий.
+func (h *Hub) Stats() *models.StreamStats {
+	h.mu.Lock()
+	subscribers := len(h.subscribers)
+	h.mu.Unlock()
+
+	return &models.StreamStats{
+		Subscribers: subscribers,
+		Published:   h.published.Load(),
+		Delivered:   h.delivered.Load(),
+		Evicted:     h.evicted.Load(),
+	}
+}
+
+// close удаляет подписку и закрывает её канал. Вызывается под h.mu, поэтому
+// отправка в закрытый канал из Publish невозможна.
+func (h *Hub) close(sub *Subscription, err error) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	sub.err = err
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
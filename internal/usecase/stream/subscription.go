@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testYTask/internal/domain/models"
+)
+
+// allCoins — монета, означающая подписку на все монеты.
+const allCoins = "*"
+
+// Subscription — подписка одного соединения на поток цен.
+//
+// Фильтр изменяется из горутины чтения соединения, события отправляет Hub,
+// поэтому фильтр защищён собственным мьютексом, а канал events закрывается только хабом.
+type Subscription struct {
+	mu         sync.RWMutex
+	all        bool
+	coins      map[string]bool
+	currencies map[string]bool
+
+	events chan []*models.PriceEvent
+	err    error
+	closed bool
+}
+
+func newSubscription(filter *models.StreamFilter, buffer int) *Subscription {
+	sub := &Subscription{
+		coins:      make(map[string]bool),
+		currencies: make(map[string]bool),
+		events:     make(chan []*models.PriceEvent, buffer),
+	}
+	if filter == nil || len(filter.Coins) == 0 {
+		sub.all = true
+	}
+	if filter != nil {
+		sub.Subscribe(filter.Coins, filter.Currencies)
+	}
+	return sub
+}
+
+// Events возвращает канал новых цен, подходящих под фильтр: одно значение — цены одного запуска задачи загрузки.
+func (s *Subscription) Events() <-chan []*models.PriceEvent {
+	return s.events
+}
+
+// Err возвращает причину закрытия подписки. Значение определено после закрытия канала Events.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Filter возвращает текущие монеты и валюты подписки. Пустой список монет означает все монеты.
+func (s *Subscription) Filter() *models.StreamFilter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filter := &models.StreamFilter{
+		Coins:      keys(s.coins),
+		Currencies: keys(s.currencies),
+	}
+	if s.all {
+		filter.Coins = []string{}
+	}
+	return filter
+}
+
+// Subscribe добавляет монеты и валюты в подписку. Монета "*" подписывает на все монеты.
+func (s *Subscription) Subscribe(coins, currencies []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, coin := range coins {
+		coin = strings.ToLower(strings.TrimSpace(coin))
+		if coin == allCoins {
+			s.all = true
+			continue
+		}
+		if coin != "" {
+			s.coins[coin] = true
+		}
+	}
+	for _, currency := range currencies {
+		s.currencies[currency] = true
+	}
+}
+
+// Unsubscribe убирает монеты и валюты из подписки. Монета "*" отменяет подписку на все монеты.
+func (s *Subscription) Unsubscribe(coins, currencies []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, coin := range coins {
+		coin = strings.ToLower(strings.TrimSpace(coin))
+		if coin == allCoins {
+			s.all = false
+			s.coins = make(map[string]bool)
+			continue
+		}
+		delete(s.coins, coin)
+	}
+	for _, currency := range currencies {
+		delete(s.currencies, currency)
+	}
+}
+
+// matches сообщает, подходит ли цена под фильтр подписки.
+func (s *Subscription) matches(event *models.PriceEvent) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.all && !s.coins[event.Coin] {
+		return false
+	}
+	return len(s.currencies) == 0 || s.currencies[event.Currency]
+}
+
+// keys возвращает отсортированные ключи множества.
+func keys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
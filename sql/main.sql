@@ -1,23 +1,168 @@
--- Таблица с ценами криптовалют
+-- Таблица с ценами криптовалют.
+-- market_cap, volume_24h, change_24h — рыночные показатели в валюте котировки, если источник их вернул;
+-- "timestamp" — время обновления данных у источника.
 CREATE TABLE public.currency_prices (
                                         id serial4 NOT NULL,
                                         symbol varchar(50) NOT NULL,
                                         "timestamp" int8 NOT NULL,
                                         price int8 NOT NULL,
                                         "precision" int2 DEFAULT 8 NOT NULL,
-                                        currency text DEFAULT 'USD'::text NULL,
+                                        currency text DEFAULT 'USD'::text NOT NULL,
+                                        market_cap numeric NULL,
+                                        volume_24h numeric NULL,
+                                        change_24h numeric NULL,
                                         CONSTRAINT currency_prices_pkey PRIMARY KEY (id),
-                                        CONSTRAINT currency_prices_symbol_timestamp_key UNIQUE (symbol, "timestamp")
+                                        CONSTRAINT currency_prices_symbol_currency_timestamp_key UNIQUE (symbol, currency, "timestamp")
 );
 
 CREATE INDEX idx_currency_prices_symbol ON public.currency_prices USING btree (symbol);
 CREATE INDEX idx_timestamp ON public.currency_prices USING btree ("timestamp");
 
+-- Число сохранённых цен каждой пары (symbol, currency); поддерживается триггерами на currency_prices,
+-- чтобы список отслеживаемых монет не пересчитывал count(*) по всей истории.
+CREATE TABLE public.price_sample_counts (
+                                            symbol varchar(50) NOT NULL,
+                                            currency text NOT NULL,
+                                            samples int8 DEFAULT 0 NOT NULL,
+                                            CONSTRAINT price_sample_counts_pkey PRIMARY KEY (symbol, currency)
+);
+
+CREATE FUNCTION public.count_inserted_prices() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO public.price_sample_counts (symbol, currency, samples)
+    SELECT symbol, currency, count(*) FROM inserted GROUP BY symbol, currency
+    ON CONFLICT (symbol, currency) DO UPDATE SET samples = public.price_sample_counts.samples + EXCLUDED.samples;
+    RETURN NULL;
+END;
+$$;
+
+CREATE FUNCTION public.count_deleted_prices() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE public.price_sample_counts c
+    SET samples = c.samples - d.samples
+    FROM (SELECT symbol, currency, count(*) AS samples FROM deleted GROUP BY symbol, currency) d
+    WHERE c.symbol = d.symbol AND c.currency = d.currency;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER currency_prices_count_insert AFTER INSERT ON public.currency_prices
+    REFERENCING NEW TABLE AS inserted FOR EACH STATEMENT EXECUTE FUNCTION public.count_inserted_prices();
+CREATE TRIGGER currency_prices_count_delete AFTER DELETE ON public.currency_prices
+    REFERENCING OLD TABLE AS deleted FOR EACH STATEMENT EXECUTE FUNCTION public.count_deleted_prices();
+
+-- Начальное заполнение для уже сохранённых цен.
+INSERT INTO public.price_sample_counts (symbol, currency, samples)
+SELECT symbol, currency, count(*) FROM public.currency_prices GROUP BY symbol, currency
+ON CONFLICT (symbol, currency) DO NOTHING;
+
 -- Таблица отслеживаемых валют
 CREATE TABLE public.watched_currencies (
                                            id serial4 NOT NULL,
                                            symbol text NOT NULL,
+                                           currencies text[] DEFAULT '{USD}'::text[] NOT NULL,
                                            added_at timestamp DEFAULT now() NULL,
                                            CONSTRAINT watched_currencies_pkey PRIMARY KEY (id),
                                            CONSTRAINT watched_currencies_symbol_key UNIQUE (symbol)
 );
+
+-- Таблица котировок отдельных источников, из которых рассчитана цена в currency_prices
+CREATE TABLE public.provider_quotes (
+                                        id serial8 NOT NULL,
+                                        symbol varchar(50) NOT NULL,
+                                        provider varchar(50) NOT NULL,
+                                        "timestamp" int8 NOT NULL,
+                                        price int8 NOT NULL,
+                                        "precision" int2 DEFAULT 8 NOT NULL,
+                                        currency text DEFAULT 'USD'::text NOT NULL,
+                                        is_outlier bool DEFAULT false NOT NULL,
+                                        CONSTRAINT provider_quotes_pkey PRIMARY KEY (id),
+                                        CONSTRAINT provider_quotes_symbol_provider_currency_timestamp_key UNIQUE (symbol, provider, currency, "timestamp")
+);
+
+-- Таблица задач дозагрузки истории цен
+CREATE TABLE public.backfill_jobs (
+                                      id serial8 NOT NULL,
+                                      symbol varchar(50) NOT NULL,
+                                      currency text DEFAULT 'USD'::text NOT NULL,
+                                      provider varchar(50) NOT NULL,
+                                      from_ts int8 NOT NULL,
+                                      to_ts int8 NOT NULL,
+                                      cursor_ts int8 NOT NULL,
+                                      status varchar(16) DEFAULT 'pending'::character varying NOT NULL,
+                                      fetched int8 DEFAULT 0 NOT NULL,
+                                      inserted int8 DEFAULT 0 NOT NULL,
+                                      error text DEFAULT ''::text NOT NULL,
+                                      created_at timestamptz DEFAULT now() NOT NULL,
+                                      updated_at timestamptz DEFAULT now() NOT NULL,
+                                      CONSTRAINT backfill_jobs_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_backfill_jobs_status ON public.backfill_jobs USING btree (status, id);
+
+-- Таблица пропусков в истории цен: интервалы между соседними ценами длиннее ожидаемого
+CREATE TABLE public.price_gaps (
+                                   id serial8 NOT NULL,
+                                   symbol varchar(50) NOT NULL,
+                                   currency text DEFAULT 'USD'::text NOT NULL,
+                                   gap_start int8 NOT NULL,
+                                   gap_end int8 NOT NULL,
+                                   duration int8 NOT NULL,
+                                   status varchar(16) DEFAULT 'open'::character varying NOT NULL,
+                                   backfill_id int8 NULL,
+                                   detected_at timestamptz DEFAULT now() NOT NULL,
+                                   updated_at timestamptz DEFAULT now() NOT NULL,
+                                   CONSTRAINT price_gaps_pkey PRIMARY KEY (id),
+                                   CONSTRAINT price_gaps_symbol_currency_start_end_key UNIQUE (symbol, currency, gap_start, gap_end),
+                                   CONSTRAINT price_gaps_backfill_id_fkey FOREIGN KEY (backfill_id) REFERENCES public.backfill_jobs (id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_price_gaps_status ON public.price_gaps USING btree (status);
+
+-- Таблица правил оповещений о ценах
+CREATE TABLE public.alert_rules (
+                                    id serial8 NOT NULL,
+                                    symbol varchar(50) NOT NULL,
+                                    currency text DEFAULT 'USD'::text NOT NULL,
+                                    "condition" varchar(16) NOT NULL,
+                                    threshold numeric NOT NULL,
+                                    window_sec int8 DEFAULT 0 NOT NULL,
+                                    webhook_url text NOT NULL,
+                                    secret text NOT NULL,
+                                    enabled bool DEFAULT true NOT NULL,
+                                    triggered bool DEFAULT false NOT NULL,
+                                    last_triggered_at int8 NULL,
+                                    created_at timestamptz DEFAULT now() NOT NULL,
+                                    CONSTRAINT alert_rules_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_alert_rules_symbol ON public.alert_rules USING btree (symbol) WHERE enabled;
+
+-- Журнал доставки сработавших оповещений на вебхуки
+CREATE TABLE public.alert_deliveries (
+                                         id serial8 NOT NULL,
+                                         rule_id int8 NOT NULL,
+                                         payload jsonb NOT NULL,
+                                         status varchar(16) DEFAULT 'pending'::character varying NOT NULL,
+                                         attempts int4 DEFAULT 0 NOT NULL,
+                                         last_status_code int4 NULL,
+                                         last_error text DEFAULT ''::text NOT NULL,
+                                         next_attempt_at timestamptz DEFAULT now() NOT NULL,
+                                         created_at timestamptz DEFAULT now() NOT NULL,
+                                         delivered_at timestamptz NULL,
+                                         CONSTRAINT alert_deliveries_pkey PRIMARY KEY (id),
+                                         CONSTRAINT alert_deliveries_rule_id_fkey FOREIGN KEY (rule_id) REFERENCES public.alert_rules (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_alert_deliveries_pending ON public.alert_deliveries USING btree (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_alert_deliveries_rule_id ON public.alert_deliveries USING btree (rule_id, id);
+
+-- Последний успешно загруженный список валидных монет (используется при старте, если источник недоступен)
+CREATE TABLE public.coin_registry (
+                                      id text NOT NULL,
+                                      symbol text NOT NULL,
+                                      "name" text NOT NULL,
+                                      market_cap_rank int4 NULL,
+                                      updated_at timestamptz DEFAULT now() NOT NULL,
+                                      CONSTRAINT coin_registry_pkey PRIMARY KEY (id)
+);